@@ -3,7 +3,7 @@ package adr
 import (
 	"fmt"
 	"regexp"
-	"strconv"
+	"slices"
 	"strings"
 	"time"
 )
@@ -42,19 +42,39 @@ type ADR struct {
 	Number       int
 	Title        string
 	Date         time.Time
+	Author       string    // optional, omitted from the file when empty
+	Tags         []string  // optional keywords, listed on a "Tags:" line
+	ReviewBy     time.Time // optional date to revisit the decision by
 	Status       Status
-	StatusExtra  []string // Additional lines in status section (links, etc.)
+	StatusExtra  []string       // Additional lines in status section (links, etc.)
+	History      []StatusChange // status changes, oldest first
+	Reviews      []Review       // reviewers asked to sign off, in request order
 	Context      string
 	Decision     string
 	Consequences string
 	Filename     string
 }
 
+// ToMarkdown formats the ADR with the default scheme
 func (a *ADR) ToMarkdown() string {
+	return a.FormatMarkdown(DefaultScheme)
+}
+
+// FormatMarkdown formats the ADR, titled with the heading template of s
+func (a *ADR) FormatMarkdown(s Scheme) string {
 	var sb strings.Builder
 
-	fmt.Fprintf(&sb, "# %d. %s\n\n", a.Number, a.Title)
+	fmt.Fprintf(&sb, "%s\n\n", s.FormatHeading(a.Number, a.Title))
 	fmt.Fprintf(&sb, "Date: %s\n\n", a.Date.Format("2006-01-02"))
+	if a.Author != "" {
+		fmt.Fprintf(&sb, "Author: %s\n\n", a.Author)
+	}
+	if len(a.Tags) > 0 {
+		fmt.Fprintf(&sb, "Tags: %s\n\n", strings.Join(a.Tags, ", "))
+	}
+	if !a.ReviewBy.IsZero() {
+		fmt.Fprintf(&sb, "Review by: %s\n\n", a.ReviewBy.Format("2006-01-02"))
+	}
 	sb.WriteString("## Status\n\n")
 	sb.WriteString(string(a.Status))
 	sb.WriteString("\n")
@@ -66,6 +86,12 @@ func (a *ADR) ToMarkdown() string {
 		sb.WriteString("\n")
 	}
 	sb.WriteString("\n")
+	if len(a.History) > 0 {
+		writeHistory(&sb, a.History)
+	}
+	if len(a.Reviews) > 0 {
+		writeReviews(&sb, a.Reviews)
+	}
 	sb.WriteString("## Context\n\n")
 	sb.WriteString(a.Context)
 	sb.WriteString("\n\n")
@@ -79,13 +105,48 @@ func (a *ADR) ToMarkdown() string {
 	return sb.String()
 }
 
+// Diagnostic describes a problem found while reading an ADR file
+type Diagnostic struct {
+	File   string
+	Line   int // 1-based, 0 when the problem isn't tied to a line
+	Reason string
+	// Duplicate is set when several files share a number. Unlike other
+	// problems, the files involved are still listed.
+	Duplicate *DuplicateNumberError
+
+	number int // ADR number taken from the filename, set by List
+}
+
+func (d *Diagnostic) Error() string {
+	if d.Duplicate != nil {
+		return d.Duplicate.Error()
+	}
+	location := d.File
+	if location == "" {
+		location = "<input>"
+	}
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, d.Line)
+	}
+	return location + ": " + d.Reason
+}
+
 var (
-	titleRegex  = regexp.MustCompile(`^#\s*(\d+)\.\s*(.+)$`)
-	dateRegex   = regexp.MustCompile(`^Date:\s*(.+)$`)
-	headerRegex = regexp.MustCompile(`^##\s*(.+)$`)
+	dateRegex     = regexp.MustCompile(`^Date:\s*(.+)$`)
+	authorRegex   = regexp.MustCompile(`^Author:\s*(.+)$`)
+	tagsRegex     = regexp.MustCompile(`^Tags:\s*(.+)$`)
+	reviewByRegex = regexp.MustCompile(`^(?:Review by|Expires):\s*(.+)$`)
+	headerRegex   = regexp.MustCompile(`^##\s*(.+)$`)
 )
 
+// ParseMarkdown parses an ADR titled with the default scheme
 func ParseMarkdown(content string) (*ADR, error) {
+	return DefaultScheme.ParseMarkdown(content)
+}
+
+// ParseMarkdown parses an ADR titled with the heading template of s or the
+// default one
+func (s Scheme) ParseMarkdown(content string) (*ADR, error) {
 	adr := &ADR{}
 	lines := strings.Split(content, "\n")
 
@@ -115,6 +176,10 @@ func ParseMarkdown(content string) (*ADR, error) {
 					}
 				}
 			}
+		case historySection:
+			adr.History = parseHistory(text)
+		case reviewSection:
+			adr.Reviews = parseReviews(text)
 		case "Context":
 			adr.Context = text
 		case "Decision":
@@ -125,14 +190,21 @@ func ParseMarkdown(content string) (*ADR, error) {
 		sectionContent = nil
 	}
 
-	for _, line := range lines {
-		if match := titleRegex.FindStringSubmatch(line); match != nil {
-			num, _ := strconv.Atoi(match[1])
+	foundTitle := false
+	malformedTitle := 0
+
+	for i, line := range lines {
+		if num, title, ok := s.ParseHeading(line); ok {
 			adr.Number = num
-			adr.Title = strings.TrimSpace(match[2])
+			adr.Title = strings.TrimSpace(title)
+			foundTitle = true
 			continue
 		}
 
+		if malformedTitle == 0 && strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "##") {
+			malformedTitle = i + 1
+		}
+
 		if match := dateRegex.FindStringSubmatch(line); match != nil {
 			dateStr := strings.TrimSpace(match[1])
 			if t, err := time.Parse("2006-01-02", dateStr); err == nil {
@@ -141,6 +213,24 @@ func ParseMarkdown(content string) (*ADR, error) {
 			continue
 		}
 
+		if match := authorRegex.FindStringSubmatch(line); match != nil && currentSection == "" {
+			adr.Author = strings.TrimSpace(match[1])
+			continue
+		}
+
+		if match := tagsRegex.FindStringSubmatch(line); match != nil && currentSection == "" {
+			adr.Tags = ParseTags(match[1])
+			continue
+		}
+
+		// "Expires:" is read as another name of the review date
+		if match := reviewByRegex.FindStringSubmatch(line); match != nil && currentSection == "" {
+			if t, err := time.Parse("2006-01-02", strings.TrimSpace(match[1])); err == nil {
+				adr.ReviewBy = t
+			}
+			continue
+		}
+
 		if match := headerRegex.FindStringSubmatch(line); match != nil {
 			flushSection()
 			currentSection = strings.TrimSpace(match[1])
@@ -154,9 +244,40 @@ func ParseMarkdown(content string) (*ADR, error) {
 
 	flushSection()
 
+	if !foundTitle {
+		expected := s.withDefaults()
+		heading := strings.NewReplacer("{number}", "<number>", "{id}", expected.Prefix+"-<number>", "{title}", "<title>").
+			Replace(expected.Heading)
+		if malformedTitle > 0 {
+			return nil, &Diagnostic{
+				Line:   malformedTitle,
+				Reason: fmt.Sprintf("malformed title line %q (expected %q)", strings.TrimSpace(lines[malformedTitle-1]), "# "+heading),
+			}
+		}
+		return nil, &Diagnostic{Line: 1, Reason: fmt.Sprintf("missing title line (expected %q)", "# "+heading)}
+	}
+
 	return adr, nil
 }
 
+// ParseTags splits a comma-separated tag list, dropping empty entries and
+// duplicates. Tags are compared case-insensitively and stored in lower case.
+func ParseTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// HasTag reports whether the ADR is tagged with tag, ignoring case
+func (a *ADR) HasTag(tag string) bool {
+	return slices.Contains(a.Tags, strings.ToLower(strings.TrimSpace(tag)))
+}
+
 func Slugify(title string) string {
 	slug := strings.ToLower(title)
 	slug = regexp.MustCompile(`[^a-z0-9\s-]`).ReplaceAllString(slug, "")
@@ -166,6 +287,7 @@ func Slugify(title string) string {
 	return slug
 }
 
+// FormatFilename returns the filename of an ADR under DefaultScheme
 func FormatFilename(number int, title string) string {
-	return fmt.Sprintf("%04d-%s.md", number, Slugify(title))
+	return DefaultScheme.filename(number, Slugify(title), "")
 }
@@ -1,6 +1,8 @@
 package adr
 
 import (
+	"errors"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -72,6 +74,30 @@ func TestSlugify(t *testing.T) {
 	}
 }
 
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"single", "security", []string{"security"}},
+		{"comma separated", "security, storage", []string{"security", "storage"}},
+		{"lower cased", "Security,API", []string{"security", "api"}},
+		{"empty entries", " , security,, ", []string{"security"}},
+		{"duplicates", "api, API, api", []string{"api"}},
+		{"empty string", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseTags(tt.input)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFormatFilename(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -394,6 +420,8 @@ func TestParseMarkdownRoundTrip(t *testing.T) {
 		Number:       1,
 		Title:        "Record Architecture Decisions",
 		Date:         date,
+		Author:       "Jane Doe",
+		Tags:         []string{"storage", "security"},
 		Status:       StatusAccepted,
 		StatusExtra:  []string{"Amends [ADR 0](0000-initial.md)"},
 		Context:      "We need to record architectural decisions.",
@@ -413,6 +441,12 @@ func TestParseMarkdownRoundTrip(t *testing.T) {
 	if parsed.Title != original.Title {
 		t.Errorf("Title = %q, want %q", parsed.Title, original.Title)
 	}
+	if parsed.Author != original.Author {
+		t.Errorf("Author = %q, want %q", parsed.Author, original.Author)
+	}
+	if !slices.Equal(parsed.Tags, original.Tags) {
+		t.Errorf("Tags = %v, want %v", parsed.Tags, original.Tags)
+	}
 	if parsed.Status != original.Status {
 		t.Errorf("Status = %q, want %q", parsed.Status, original.Status)
 	}
@@ -453,3 +487,53 @@ func TestValidStatuses(t *testing.T) {
 		}
 	}
 }
+
+func TestParseMarkdownDiagnostics(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantLine   int
+		wantReason string
+	}{
+		{
+			name:       "malformed title",
+			input:      "\n# 7 Use Kafka\n\nDate: 2024-01-15\n\n## Status\n\nDraft\n",
+			wantLine:   2,
+			wantReason: "malformed title line",
+		},
+		{
+			name:       "missing title",
+			input:      "Date: 2024-01-15\n\n## Status\n\nDraft\n",
+			wantLine:   1,
+			wantReason: "missing title line",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMarkdown(tt.input)
+			var d *Diagnostic
+			if !errors.As(err, &d) {
+				t.Fatalf("ParseMarkdown() error = %v, want *Diagnostic", err)
+			}
+			if d.Line != tt.wantLine {
+				t.Errorf("Line = %d, want %d", d.Line, tt.wantLine)
+			}
+			if !strings.Contains(d.Reason, tt.wantReason) {
+				t.Errorf("Reason = %q, want to contain %q", d.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestDiagnosticError(t *testing.T) {
+	d := &Diagnostic{File: "0007-broken.md", Line: 3, Reason: "bad"}
+	if got := d.Error(); got != "0007-broken.md:3: bad" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	d = &Diagnostic{File: "0007-broken.md", Reason: "bad"}
+	if got := d.Error(); got != "0007-broken.md: bad" {
+		t.Errorf("Error() = %q", got)
+	}
+}
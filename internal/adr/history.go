@@ -0,0 +1,140 @@
+package adr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// historySection is the heading of the section listing status changes
+const historySection = "Status history"
+
+// StatusChange is a status transition recorded in an ADR
+type StatusChange struct {
+	Date   time.Time
+	From   Status
+	To     Status
+	Author string // optional
+	Reason string // optional
+
+	date string // the date as written, kept when it doesn't parse
+}
+
+// SetStatus changes the status of the ADR and records the transition in its
+// history. Setting the current status again records nothing and returns false.
+func (a *ADR) SetStatus(status Status, date time.Time, author, reason string) bool {
+	if status == a.Status {
+		return false
+	}
+	a.History = append(a.History, StatusChange{
+		Date:   date,
+		From:   a.Status,
+		To:     status,
+		Author: strings.TrimSpace(author),
+		Reason: strings.Join(strings.Fields(reason), " "),
+	})
+	a.Status = status
+	return true
+}
+
+// LastChanged returns the date of the latest recorded status change, or the
+// zero time when the history is empty
+func (a *ADR) LastChanged() time.Time {
+	var last time.Time
+	for _, c := range a.History {
+		if c.Date.After(last) {
+			last = c.Date
+		}
+	}
+	return last
+}
+
+// writeHistory writes the status history section as a Markdown table
+func writeHistory(sb *strings.Builder, history []StatusChange) {
+	sb.WriteString("## " + historySection + "\n\n")
+	sb.WriteString("| Date | From | To | Author | Reason |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, c := range history {
+		date := c.date
+		if !c.Date.IsZero() {
+			date = c.Date.Format("2006-01-02")
+		}
+		cells := []string{date, string(c.From), string(c.To), c.Author, c.Reason}
+		for i, cell := range cells {
+			cells[i] = escapeCell(cell)
+		}
+		fmt.Fprintf(sb, "| %s |\n", strings.Join(cells, " | "))
+	}
+	sb.WriteString("\n")
+}
+
+// parseHistory reads the rows of the status history table. The header and
+// separator rows, and lines outside the table, are skipped.
+func parseHistory(text string) []StatusChange {
+	var history []StatusChange
+	for _, line := range strings.Split(text, "\n") {
+		cells := splitRow(line)
+		if len(cells) < 3 || cells[0] == "Date" || strings.Trim(cells[0], "-: ") == "" {
+			continue
+		}
+		for len(cells) < 5 {
+			cells = append(cells, "")
+		}
+
+		c := StatusChange{
+			From:   parseHistoryStatus(cells[1]),
+			To:     parseHistoryStatus(cells[2]),
+			Author: cells[3],
+			Reason: cells[4],
+		}
+		if t, err := time.Parse("2006-01-02", cells[0]); err == nil {
+			c.Date = t
+		} else {
+			c.date = cells[0]
+		}
+		history = append(history, c)
+	}
+	return history
+}
+
+// parseHistoryStatus normalizes a status of the history, keeping unknown
+// ones as written like the Status section does
+func parseHistoryStatus(s string) Status {
+	if status, err := ParseStatus(s); err == nil {
+		return status
+	}
+	return Status(s)
+}
+
+// splitRow returns the cells of a Markdown table row, or nil if line isn't one
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "|") {
+		return nil
+	}
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// escapeCell keeps a value on one line and within its table cell
+func escapeCell(s string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(s), " "), "|", `\|`)
+}
@@ -0,0 +1,120 @@
+package adr
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetStatus(t *testing.T) {
+	a := NewADR(1, "Use Kafka")
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	if !a.SetStatus(StatusProposed, day, "Jane Doe", "") {
+		t.Fatal("SetStatus() = false, want a recorded change")
+	}
+	if !a.SetStatus(StatusAccepted, day.AddDate(0, 0, 3), "", "Approved at the\narchitecture review") {
+		t.Fatal("SetStatus() = false, want a recorded change")
+	}
+	if a.SetStatus(StatusAccepted, day.AddDate(0, 0, 4), "", "") {
+		t.Error("SetStatus() to the current status = true, want false")
+	}
+
+	want := []StatusChange{
+		{Date: day, From: StatusDraft, To: StatusProposed, Author: "Jane Doe"},
+		{Date: day.AddDate(0, 0, 3), From: StatusProposed, To: StatusAccepted, Reason: "Approved at the architecture review"},
+	}
+	if len(a.History) != len(want) {
+		t.Fatalf("History = %+v, want %d changes", a.History, len(want))
+	}
+	for i := range want {
+		if a.History[i] != want[i] {
+			t.Errorf("History[%d] = %+v, want %+v", i, a.History[i], want[i])
+		}
+	}
+	if a.Status != StatusAccepted {
+		t.Errorf("Status = %s, want Accepted", a.Status)
+	}
+	if got := a.LastChanged(); !got.Equal(day.AddDate(0, 0, 3)) {
+		t.Errorf("LastChanged() = %v, want the acceptance date", got)
+	}
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	a := NewADR(2, "Use Kafka")
+	a.Date = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	a.SetStatus(StatusProposed, time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), "Jane Doe", "")
+	a.SetStatus(StatusRejected, time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), "", "Too costly | too slow")
+
+	content := a.ToMarkdown()
+	for _, want := range []string{
+		"## Status\n\nRejected\n\n## Status history\n\n",
+		"| 2026-10-02 | Draft | Proposed | Jane Doe |  |\n",
+		"| 2026-10-05 | Proposed | Rejected |  | Too costly \\| too slow |\n\n## Context",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("ToMarkdown() missing %q in:\n%s", want, content)
+		}
+	}
+
+	parsed, err := ParseMarkdown(content)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error: %v", err)
+	}
+	if len(parsed.History) != 2 {
+		t.Fatalf("History = %+v, want 2 changes", parsed.History)
+	}
+	for i := range a.History {
+		if parsed.History[i] != a.History[i] {
+			t.Errorf("History[%d] = %+v, want %+v", i, parsed.History[i], a.History[i])
+		}
+	}
+	if parsed.Context != a.Context {
+		t.Errorf("Context = %q, want %q", parsed.Context, a.Context)
+	}
+	if parsed.ToMarkdown() != content {
+		t.Errorf("round trip changed the content:\n%s", parsed.ToMarkdown())
+	}
+}
+
+func TestParseHistoryEdited(t *testing.T) {
+	// Hand-written rows may omit cells, use other cases or a bad date
+	content := `# 3. Use Kafka
+
+Date: 2026-10-01
+
+## Status
+
+Accepted
+
+## Status history
+
+|Date|From|To|
+|:--|:--|:--|
+|2026-10-02|draft|accepted|
+| last week | Accepted | Accepted |
+
+## Context
+
+Context.
+`
+
+	a, err := ParseMarkdown(content)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error: %v", err)
+	}
+	if len(a.History) != 2 {
+		t.Fatalf("History = %+v, want 2 changes", a.History)
+	}
+	if c := a.History[0]; c.From != StatusDraft || c.To != StatusAccepted || c.Date.Format("2006-01-02") != "2026-10-02" {
+		t.Errorf("History[0] = %+v, want Draft to Accepted on 2026-10-02", c)
+	}
+
+	// The unparsed date is written back as it was
+	if !strings.Contains(a.ToMarkdown(), "| last week | Accepted | Accepted |  |  |\n") {
+		t.Errorf("ToMarkdown() lost the row with an unparsed date:\n%s", a.ToMarkdown())
+	}
+	if got := a.LastChanged(); got.Format("2006-01-02") != "2026-10-02" {
+		t.Errorf("LastChanged() = %v, want 2026-10-02", got)
+	}
+}
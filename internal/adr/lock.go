@@ -0,0 +1,123 @@
+package adr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LockFileName is the advisory lock file created in the ADR directory while
+// stamp modifies it
+const LockFileName = ".stamp.lock"
+
+var (
+	// lockTimeout is how long to wait for another process to release the lock
+	lockTimeout = 10 * time.Second
+	// lockStaleAfter is the age after which a lock is assumed to be left over
+	// from a crashed process and removed
+	lockStaleAfter = 30 * time.Second
+	// lockRetryInterval is the delay between attempts to acquire the lock
+	lockRetryInterval = 20 * time.Millisecond
+)
+
+// ErrLocked is returned when the ADR directory stays locked by another process
+var ErrLocked = errors.New("ADR directory is locked by another stamp process")
+
+// lock acquires the advisory lock of the store and returns a function that
+// releases it. The lock is a file created with O_EXCL, which works on every
+// platform and filesystem stamp supports.
+func (s *Store) lock() (func(), error) {
+	path := filepath.Join(s.Directory, LockFileName)
+	// The process ID tells users who holds the lock; the time tells apart
+	// acquisitions within one process
+	owner := fmt.Sprintf("%d %d", os.Getpid(), time.Now().UnixNano())
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, _ = f.WriteString(owner)
+			_ = f.Close()
+			return func() { unlock(path, owner) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > lockStaleAfter {
+			breakStaleLock(path, owner)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w (remove %s if no other stamp is running)", ErrLocked, path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// breakStaleLock removes a lock left over by a crashed process. The lock is
+// renamed to a name private to owner first, so when several processes
+// find the same stale lock only one of them takes it away. If the renamed lock
+// turns out to be fresh, another process took over the stale one in between
+// and its lock is put back; Link fails rather than replacing a newer lock.
+func breakStaleLock(path, owner string) {
+	taken := path + "." + strings.ReplaceAll(owner, " ", "-")
+	if err := os.Rename(path, taken); err != nil {
+		return
+	}
+	if info, err := os.Stat(taken); err == nil && time.Since(info.ModTime()) <= lockStaleAfter {
+		_ = os.Link(taken, path)
+	}
+	_ = os.Remove(taken)
+}
+
+// unlock removes the lock at path if it still belongs to owner, so a process
+// whose lock was taken over as stale doesn't release the new owner's lock
+func unlock(path, owner string) {
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != owner {
+		return
+	}
+	_ = os.Remove(path)
+}
+
+// WithLock runs fn while holding the store's advisory lock. Use it around
+// read-modify-write operations so concurrent stamp invocations don't
+// overwrite each other's changes.
+func (s *Store) WithLock(fn func() error) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return fn()
+}
+
+// WithLocks runs fn while holding the locks of every distinct store, taken
+// in directory order so concurrent invocations cannot deadlock
+func WithLocks(stores []*Store, fn func() error) error {
+	seen := make(map[string]bool)
+	var unique []*Store
+	for _, s := range stores {
+		if !seen[s.Directory] {
+			seen[s.Directory] = true
+			unique = append(unique, s)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool {
+		return unique[i].Directory < unique[j].Directory
+	})
+
+	locked := fn
+	for i := len(unique) - 1; i >= 0; i-- {
+		s, next := unique[i], locked
+		locked = func() error { return s.WithLock(next) }
+	}
+	return locked()
+}
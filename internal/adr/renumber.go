@@ -0,0 +1,177 @@
+package adr
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RenumberResult describes the files changed by Renumber
+type RenumberResult struct {
+	Number      int
+	OldFilename string
+	NewFilename string
+	Updated     []string // other ADRs whose links were rewritten, relative to the directory
+}
+
+// linkRegex matches links to target such as "[ADR-0042](0042-title.md)",
+// capturing the ID prefix and number
+func linkRegex(target string) *regexp.Regexp {
+	return regexp.MustCompile(`\[([A-Za-z][A-Za-z0-9]*)-(\d+)\]\(` + regexp.QuoteMeta(target) + `\)`)
+}
+
+// LinkTarget returns the target of a link to filename in the directory to,
+// as written in an ADR of the directory from: the bare filename within one
+// directory, a relative path across collections
+func LinkTarget(from, to, filename string) string {
+	if from == to {
+		return filename
+	}
+	rel, err := filepath.Rel(from, filepath.Join(to, filename))
+	if err != nil {
+		return filename
+	}
+	return filepath.ToSlash(rel)
+}
+
+// stores returns s followed by its linked stores, without repeating a
+// directory
+func (s *Store) stores() []*Store {
+	stores := []*Store{s}
+	for _, linked := range s.Linked {
+		if !slices.ContainsFunc(stores, func(o *Store) bool { return o.Directory == linked.Directory }) {
+			stores = append(stores, linked)
+		}
+	}
+	return stores
+}
+
+// relink returns the changes pointing every link to filename in the other
+// ADRs of the store and its linked stores at newFilename, and the paths of
+// the files changed relative to the store. If number is not 0, the IDs of
+// the links are renumbered as well.
+func (s *Store) relink(filename, newFilename string, number int) ([]fileChange, []string, error) {
+	id := "${2}"
+	if number != 0 {
+		id = s.Scheme.FormatNumber(number)
+	}
+
+	var changes []fileChange
+	var names []string
+	for i, linking := range s.stores() {
+		entries, err := os.ReadDir(linking.Directory)
+		if i > 0 && errors.Is(err, fs.ErrNotExist) {
+			// A collection without ADRs yet has nothing to relink
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		links := linkRegex(LinkTarget(linking.Directory, s.Directory, filename))
+		target := LinkTarget(linking.Directory, s.Directory, newFilename)
+		replacement := fmt.Sprintf("[${1}-%s](%s)", id, strings.ReplaceAll(target, "$", "$$"))
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || (i == 0 && name == filename) || !linking.Scheme.Matches(name) {
+				continue
+			}
+			path := filepath.Join(linking.Directory, name)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			updated := links.ReplaceAllString(string(data), replacement)
+			if updated != string(data) {
+				changes = append(changes, fileChange{path: path, content: []byte(updated)})
+				names = append(names, LinkTarget(s.Directory, linking.Directory, name))
+			}
+		}
+	}
+	return changes, names, nil
+}
+
+// Renumber gives the ADR stored in filename a new number: the file is
+// renamed, its heading rewritten and every link to it from other ADRs
+// updated, all as one change. If to is 0, the next free number is used.
+func (s *Store) Renumber(filename string, to int) (*RenumberResult, error) {
+	parts, ok := s.Scheme.ParseFilename(filename)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an ADR file", filename)
+	}
+
+	var result *RenumberResult
+	err := WithLocks(s.stores(), func() error {
+		content, err := os.ReadFile(filepath.Join(s.Directory, filename))
+		if err != nil {
+			return err
+		}
+
+		if to == 0 {
+			to, err = s.NextNumber()
+			if err != nil {
+				return err
+			}
+		}
+		if s.numberTaken(to, filename) {
+			return fmt.Errorf("ADR %s already exists", s.Scheme.FormatNumber(to))
+		}
+
+		newFilename := s.Scheme.filename(to, parts.Slug, parts.Date)
+		result = &RenumberResult{Number: to, OldFilename: filename, NewFilename: newFilename}
+
+		var changes []fileChange
+		changes = append(changes, fileChange{
+			path:    filepath.Join(s.Directory, newFilename),
+			content: []byte(s.Scheme.renumberHeading(string(content), to)),
+		})
+		if newFilename != filename {
+			changes = append(changes, fileChange{path: filepath.Join(s.Directory, filename), remove: true})
+		}
+
+		relinks, updated, err := s.relink(filename, newFilename, to)
+		if err != nil {
+			return err
+		}
+		result.Updated = updated
+
+		return applyChanges(append(changes, relinks...))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// renumberHeading replaces the number in the first title line of content,
+// keeping the rest of the line as written
+func (s Scheme) renumberHeading(content string, number int) string {
+	return s.rewriteHeading(content, func(line string, m headingMatch) string {
+		formatted := strconv.Itoa(number)
+		if m.padded {
+			formatted = s.FormatNumber(number)
+		}
+		return line[:m.number[0]] + formatted + line[m.number[1]:]
+	})
+}
+
+// rewriteHeading replaces the first title line of content with the result
+// of rewrite
+func (s Scheme) rewriteHeading(content string, rewrite func(line string, m headingMatch) string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if m, ok := s.matchHeading(line); ok {
+			lines[i] = rewrite(line, m)
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
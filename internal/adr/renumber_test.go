@@ -0,0 +1,169 @@
+package adr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenumberHeading(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"standard", "# 42. Use Kafka\n\nDate: 2024-01-15\n", "# 45. Use Kafka\n\nDate: 2024-01-15\n"},
+		{"no space", "#42. Use Kafka\n", "#45. Use Kafka\n"},
+		{"only first heading", "# 42. Use Kafka\n# 42. Again\n", "# 45. Use Kafka\n# 42. Again\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultScheme.renumberHeading(tt.input, 45); got != tt.want {
+				t.Errorf("renumberHeading() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreCollisions(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	for _, a := range []*ADR{NewADR(1, "First"), NewADR(2, "Kafka"), NewADR(2, "Pulsar")} {
+		if err := store.Save(a); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+
+	adrs, diagnostics, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(adrs) != 3 {
+		t.Errorf("List() returned %d ADRs, want all 3", len(adrs))
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Duplicate == nil {
+		t.Fatalf("List() diagnostics = %v, want one collision", diagnostics)
+	}
+	if got := diagnostics[0].Duplicate.Filenames; len(got) != 2 {
+		t.Errorf("collision diagnostic filenames = %v, want 2", got)
+	}
+
+	collisions := Collisions(adrs)
+	if len(collisions) != 1 {
+		t.Fatalf("Collisions() returned %d collisions, want 1", len(collisions))
+	}
+	if collisions[0].Number != 2 {
+		t.Errorf("collision number = %d, want 2", collisions[0].Number)
+	}
+	want := []string{"0002-kafka.md", "0002-pulsar.md"}
+	if strings.Join(collisions[0].Filenames, ",") != strings.Join(want, ",") {
+		t.Errorf("collision filenames = %v, want %v", collisions[0].Filenames, want)
+	}
+
+	_, err = store.FindByNumber(2)
+	var dup *DuplicateNumberError
+	if !errors.As(err, &dup) {
+		t.Errorf("FindByNumber(2) error = %v, want DuplicateNumberError", err)
+	}
+}
+
+func TestDuplicateNumberErrorUsesScheme(t *testing.T) {
+	repo := NewMemoryRepository()
+	repo.Scheme = Scheme{Width: 3}
+	for _, a := range []*ADR{NewADR(7, "Kafka"), NewADR(7, "Pulsar")} {
+		if err := repo.Save(a); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+
+	_, err := FindByNumber(repo, 7)
+	want := "ADR 007 is used by 2 files: 007-kafka.md, 007-pulsar.md (run 'stamp renumber 007-pulsar.md' to resolve)"
+	if err == nil || err.Error() != want {
+		t.Errorf("FindByNumber(7) error = %v, want %q", err, want)
+	}
+}
+
+func TestStoreRenumber(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	kafka := NewADR(2, "Kafka")
+	pulsar := NewADR(2, "Pulsar")
+	linking := NewADR(3, "Linking")
+	linking.StatusExtra = []string{"Amends [ADR-0002](0002-pulsar.md)", "Clarifies [ADR-0002](0002-kafka.md)"}
+	for _, a := range []*ADR{NewADR(1, "First"), kafka, pulsar, linking} {
+		if err := store.Save(a); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+
+	result, err := store.Renumber("0002-pulsar.md", 0)
+	if err != nil {
+		t.Fatalf("Renumber() error: %v", err)
+	}
+
+	if result.Number != 4 || result.NewFilename != "0004-pulsar.md" {
+		t.Errorf("Renumber() = %d/%q, want 4/%q", result.Number, result.NewFilename, "0004-pulsar.md")
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != "0003-linking.md" {
+		t.Errorf("Updated = %v, want [0003-linking.md]", result.Updated)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "0002-pulsar.md")); !os.IsNotExist(err) {
+		t.Error("Renumber() did not remove the old file")
+	}
+
+	renumbered, err := store.Load("0004-pulsar.md")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if renumbered.Number != 4 {
+		t.Errorf("heading number = %d, want 4", renumbered.Number)
+	}
+
+	updated, err := store.Load("0003-linking.md")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if updated.StatusExtra[0] != "Amends [ADR-0004](0004-pulsar.md)" {
+		t.Errorf("StatusExtra[0] = %q, want link to the new file", updated.StatusExtra[0])
+	}
+	if updated.StatusExtra[1] != "Clarifies [ADR-0002](0002-kafka.md)" {
+		t.Errorf("StatusExtra[1] = %q, link to the other ADR 0002 must not change", updated.StatusExtra[1])
+	}
+
+	if _, err := store.FindByNumber(2); err != nil {
+		t.Errorf("FindByNumber(2) error after renumber: %v", err)
+	}
+}
+
+func TestStoreRenumberTo(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	for _, a := range []*ADR{NewADR(1, "First"), NewADR(2, "Second")} {
+		if err := store.Save(a); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+
+	if _, err := store.Renumber("0002-second.md", 1); err == nil {
+		t.Error("Renumber() expected error when the number is taken")
+	}
+
+	result, err := store.Renumber("0002-second.md", 10)
+	if err != nil {
+		t.Fatalf("Renumber() error: %v", err)
+	}
+	if result.NewFilename != "0010-second.md" {
+		t.Errorf("NewFilename = %q, want %q", result.NewFilename, "0010-second.md")
+	}
+
+	if _, err := store.Renumber("README.md", 3); err == nil {
+		t.Error("Renumber() expected error for a non-ADR file")
+	}
+}
@@ -0,0 +1,422 @@
+package adr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Repository is a collection of ADRs. Store is the filesystem implementation
+// used by default; FSRepository reads ADRs from any fs.FS (a git tree, an
+// archive or an embedded bundle) and MemoryRepository keeps them in memory.
+type Repository interface {
+	// List loads every ADR, sorted by number. Files that cannot be parsed
+	// are reported as diagnostics.
+	List() ([]*ADR, []*Diagnostic, error)
+	// Load reads and parses the ADR stored in filename
+	Load(filename string) (*ADR, error)
+	// Save writes an ADR, deriving its filename from its number and title
+	// when it has none yet
+	Save(adr *ADR) error
+	// Delete removes the ADR stored in filename
+	Delete(filename string) error
+	// NextNumber returns the number the next new ADR should use
+	NextNumber() (int, error)
+	// Watch reports changes to ADR files until ctx is cancelled, after which
+	// the returned channel is closed
+	Watch(ctx context.Context) (<-chan Event, error)
+}
+
+var (
+	_ Repository = (*Store)(nil)
+	_ Repository = (*FSRepository)(nil)
+	_ Repository = (*MemoryRepository)(nil)
+)
+
+// ErrReadOnly is returned when writing to a repository that cannot be modified
+var ErrReadOnly = errors.New("ADR repository is read-only")
+
+// EventKind is the kind of change reported by Watch
+type EventKind int
+
+const (
+	EventCreated EventKind = iota
+	EventModified
+	EventRemoved
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventCreated:
+		return "created"
+	case EventModified:
+		return "modified"
+	case EventRemoved:
+		return "removed"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// Event is a change to an ADR file
+type Event struct {
+	Kind     EventKind
+	Filename string
+}
+
+// watchInterval is how often Watch polls for changes
+var watchInterval = 500 * time.Millisecond
+
+// FindByNumber looks up an ADR by number in r. It returns a
+// *DuplicateNumberError if several files use the number, the file's
+// *Diagnostic if the only file with the number cannot be parsed, and
+// fs.ErrNotExist otherwise.
+func FindByNumber(r Repository, number int) (*ADR, error) {
+	adrs, diagnostics, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*ADR
+	for _, adr := range adrs {
+		if adr.Number == number {
+			matches = append(matches, adr)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		// Report why a file with this number was skipped, if there is one
+		for _, d := range diagnostics {
+			if d.number == number {
+				return nil, d
+			}
+		}
+		return nil, fs.ErrNotExist
+	case 1:
+		return matches[0], nil
+	default:
+		for _, d := range diagnostics {
+			if d.Duplicate != nil && d.Duplicate.Number == number {
+				return nil, d.Duplicate
+			}
+		}
+		return nil, &DuplicateNumberError{Collision: Collisions(matches)[0]}
+	}
+}
+
+// parseADR parses content as the ADR stored in filename under scheme
+func parseADR(scheme Scheme, filename string, content []byte) (*ADR, error) {
+	adr, err := scheme.ParseMarkdown(string(content))
+	if err != nil {
+		var d *Diagnostic
+		if errors.As(err, &d) {
+			d.File = filename
+		}
+		return nil, err
+	}
+
+	adr.Filename = filename
+	return adr, nil
+}
+
+// listADRs loads the ADR files among names with load, sorted by number.
+// Files that fail to load and numbers used by several files are returned as
+// diagnostics.
+func listADRs(scheme Scheme, names []string, load func(string) (*ADR, error)) ([]*ADR, []*Diagnostic) {
+	var adrs []*ADR
+	var diagnostics []*Diagnostic
+	for _, name := range names {
+		parts, ok := scheme.ParseFilename(name)
+		if !ok {
+			continue
+		}
+
+		adr, err := load(name)
+		if err != nil {
+			var d *Diagnostic
+			if !errors.As(err, &d) {
+				d = &Diagnostic{File: name, Reason: err.Error()}
+			}
+			d.number = parts.Number
+			diagnostics = append(diagnostics, d)
+			continue
+		}
+
+		adrs = append(adrs, adr)
+	}
+
+	sort.Slice(adrs, func(i, j int) bool {
+		if adrs[i].Number != adrs[j].Number {
+			return adrs[i].Number < adrs[j].Number
+		}
+		return adrs[i].Filename < adrs[j].Filename
+	})
+
+	for _, c := range Collisions(adrs) {
+		dup := &DuplicateNumberError{Collision: c, Scheme: scheme}
+		diagnostics = append(diagnostics, &Diagnostic{Reason: dup.Error(), Duplicate: dup, number: c.Number})
+	}
+
+	return adrs, diagnostics
+}
+
+// poll calls snapshot every watchInterval and sends an event for every ADR
+// file whose version changed, until ctx is cancelled. snapshot maps file
+// names to an opaque version that changes whenever the file does.
+func poll(ctx context.Context, scheme Scheme, snapshot func() (map[string]string, error)) (<-chan Event, error) {
+	prev, err := snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			next, err := snapshot()
+			if err != nil {
+				continue
+			}
+
+			for _, event := range diffSnapshots(scheme, prev, next) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			prev = next
+		}
+	}()
+
+	return events, nil
+}
+
+// diffSnapshots returns the events that turn prev into next, sorted by filename
+func diffSnapshots(scheme Scheme, prev, next map[string]string) []Event {
+	var events []Event
+	for name, version := range next {
+		if !scheme.Matches(name) {
+			continue
+		}
+		old, ok := prev[name]
+		switch {
+		case !ok:
+			events = append(events, Event{Kind: EventCreated, Filename: name})
+		case old != version:
+			events = append(events, Event{Kind: EventModified, Filename: name})
+		}
+	}
+	for name := range prev {
+		if _, ok := next[name]; !ok && scheme.Matches(name) {
+			events = append(events, Event{Kind: EventRemoved, Filename: name})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Filename < events[j].Filename
+	})
+	return events
+}
+
+// FSRepository is a read-only repository backed by an fs.FS whose root is the
+// ADR directory. Use fs.Sub to point it at a subdirectory.
+type FSRepository struct {
+	FS fs.FS
+	// Scheme names and numbers the ADR files; the zero value is DefaultScheme
+	Scheme Scheme
+}
+
+func NewFSRepository(fsys fs.FS) *FSRepository {
+	return &FSRepository{FS: fsys, Scheme: DefaultScheme}
+}
+
+// names returns the names of the regular files in the repository
+func (r *FSRepository) names() ([]string, error) {
+	entries, err := fs.ReadDir(r.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func (r *FSRepository) List() ([]*ADR, []*Diagnostic, error) {
+	names, err := r.names()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	adrs, diagnostics := listADRs(r.Scheme, names, r.Load)
+	return adrs, diagnostics, nil
+}
+
+func (r *FSRepository) Load(filename string) (*ADR, error) {
+	content, err := fs.ReadFile(r.FS, filename)
+	if err != nil {
+		return nil, err
+	}
+	return parseADR(r.Scheme, filename, content)
+}
+
+func (r *FSRepository) Save(adr *ADR) error {
+	return ErrReadOnly
+}
+
+func (r *FSRepository) Delete(filename string) error {
+	return ErrReadOnly
+}
+
+func (r *FSRepository) NextNumber() (int, error) {
+	names, err := r.names()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 1, nil
+		}
+		return 0, err
+	}
+	return r.Scheme.NextNumber(names, time.Now())
+}
+
+// Watch reports changes visible through the file system's modification
+// times. Immutable file systems such as git trees never report any.
+func (r *FSRepository) Watch(ctx context.Context) (<-chan Event, error) {
+	return poll(ctx, r.Scheme, func() (map[string]string, error) {
+		entries, err := fs.ReadDir(r.FS, ".")
+		if err != nil {
+			return nil, err
+		}
+
+		versions := make(map[string]string, len(entries))
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			versions[entry.Name()] = fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+		}
+		return versions, nil
+	})
+}
+
+// MemoryRepository keeps ADRs in memory. It is safe for concurrent use and
+// mainly intended for tests and tools embedding stamp.
+type MemoryRepository struct {
+	// Scheme names and numbers the ADR files; the zero value is DefaultScheme
+	Scheme Scheme
+
+	mu       sync.Mutex
+	files    map[string][]byte
+	versions map[string]int
+	revision int
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		Scheme:   DefaultScheme,
+		files:    make(map[string][]byte),
+		versions: make(map[string]int),
+	}
+}
+
+// Files returns the filenames in the repository, sorted
+func (r *MemoryRepository) Files() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.files))
+	for name := range r.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteFile stores raw content under filename, bypassing ADR formatting
+func (r *MemoryRepository) WriteFile(filename string, content []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revision++
+	r.files[filename] = append([]byte(nil), content...)
+	r.versions[filename] = r.revision
+}
+
+func (r *MemoryRepository) List() ([]*ADR, []*Diagnostic, error) {
+	adrs, diagnostics := listADRs(r.Scheme, r.Files(), r.Load)
+	return adrs, diagnostics, nil
+}
+
+func (r *MemoryRepository) Load(filename string) (*ADR, error) {
+	r.mu.Lock()
+	content, ok := r.files[filename]
+	r.mu.Unlock()
+
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: filename, Err: fs.ErrNotExist}
+	}
+	return parseADR(r.Scheme, filename, content)
+}
+
+func (r *MemoryRepository) Save(adr *ADR) error {
+	if adr.Filename == "" {
+		adr.Filename = r.Scheme.FormatFilename(adr.Number, adr.Title, adr.Date)
+	}
+
+	r.WriteFile(adr.Filename, []byte(adr.FormatMarkdown(r.Scheme)))
+	return nil
+}
+
+func (r *MemoryRepository) Delete(filename string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.files[filename]; !ok {
+		return &fs.PathError{Op: "remove", Path: filename, Err: fs.ErrNotExist}
+	}
+	delete(r.files, filename)
+	delete(r.versions, filename)
+	return nil
+}
+
+func (r *MemoryRepository) NextNumber() (int, error) {
+	return r.Scheme.NextNumber(r.Files(), time.Now())
+}
+
+func (r *MemoryRepository) Watch(ctx context.Context) (<-chan Event, error) {
+	return poll(ctx, r.Scheme, func() (map[string]string, error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		versions := make(map[string]string, len(r.versions))
+		for name, version := range r.versions {
+			versions[name] = strconv.Itoa(version)
+		}
+		return versions, nil
+	})
+}
@@ -0,0 +1,224 @@
+package adr
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func testADR(number int, title string) *ADR {
+	return &ADR{
+		Number:       number,
+		Title:        title,
+		Date:         time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Status:       StatusAccepted,
+		Context:      "Context",
+		Decision:     "Decision",
+		Consequences: "Consequences",
+	}
+}
+
+func TestFSRepository(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002-second.md": {Data: []byte(testADR(2, "Second").ToMarkdown())},
+		"0001-first.md":  {Data: []byte(testADR(1, "First").ToMarkdown())},
+		"0003-broken.md": {Data: []byte("no title here\n")},
+		"README.md":      {Data: []byte("# Decisions\n")},
+		"images/a.png":   {Data: []byte{}},
+	}
+	repo := NewFSRepository(fsys)
+
+	adrs, diagnostics, err := repo.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(adrs) != 2 || adrs[0].Number != 1 || adrs[1].Number != 2 {
+		t.Fatalf("List() = %v, want ADRs 1 and 2", adrs)
+	}
+	if adrs[0].Filename != "0001-first.md" {
+		t.Errorf("Filename = %q, want %q", adrs[0].Filename, "0001-first.md")
+	}
+	if len(diagnostics) != 1 || diagnostics[0].File != "0003-broken.md" {
+		t.Errorf("diagnostics = %v, want one for 0003-broken.md", diagnostics)
+	}
+
+	next, err := repo.NextNumber()
+	if err != nil {
+		t.Fatalf("NextNumber() error: %v", err)
+	}
+	if next != 4 {
+		t.Errorf("NextNumber() = %d, want 4", next)
+	}
+
+	if err := repo.Save(testADR(4, "Fourth")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Save() error = %v, want ErrReadOnly", err)
+	}
+	if err := repo.Delete("0001-first.md"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Delete() error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestFSRepositorySub(t *testing.T) {
+	fsys := fstest.MapFS{
+		"docs/adr/0001-first.md": {Data: []byte(testADR(1, "First").ToMarkdown())},
+	}
+	sub, err := fs.Sub(fsys, "docs/adr")
+	if err != nil {
+		t.Fatalf("fs.Sub() error: %v", err)
+	}
+
+	a, err := FindByNumber(NewFSRepository(sub), 1)
+	if err != nil {
+		t.Fatalf("FindByNumber() error: %v", err)
+	}
+	if a.Title != "First" {
+		t.Errorf("Title = %q, want %q", a.Title, "First")
+	}
+}
+
+func TestFindByNumberNotFound(t *testing.T) {
+	repo := NewMemoryRepository()
+	if _, err := FindByNumber(repo, 1); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("FindByNumber() error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestMemoryRepository(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	first := testADR(1, "First Decision")
+	if err := repo.Save(first); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if first.Filename != "0001-first-decision.md" {
+		t.Errorf("Filename = %q, want %q", first.Filename, "0001-first-decision.md")
+	}
+	if err := repo.Save(testADR(2, "Second Decision")); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := repo.Load("0001-first-decision.md")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if loaded.Title != "First Decision" || loaded.Status != StatusAccepted {
+		t.Errorf("Load() = %+v, want the saved ADR", loaded)
+	}
+
+	if err := repo.Delete("0001-first-decision.md"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if _, err := repo.Load("0001-first-decision.md"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Load() after Delete() error = %v, want fs.ErrNotExist", err)
+	}
+	if err := repo.Delete("0001-first-decision.md"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("second Delete() error = %v, want fs.ErrNotExist", err)
+	}
+
+	adrs, _, err := repo.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(adrs) != 1 || adrs[0].Number != 2 {
+		t.Errorf("List() = %v, want only ADR 2", adrs)
+	}
+
+	next, _ := repo.NextNumber()
+	if next != 3 {
+		t.Errorf("NextNumber() = %d, want 3", next)
+	}
+}
+
+// collectEvents reads events until want have arrived or a timeout expires
+func collectEvents(t *testing.T, events <-chan Event, want int) []Event {
+	t.Helper()
+
+	var got []Event
+	timeout := time.After(2 * time.Second)
+	for len(got) < want {
+		select {
+		case e := <-events:
+			got = append(got, e)
+		case <-timeout:
+			t.Fatalf("got events %v, want %d", got, want)
+		}
+	}
+	return got
+}
+
+func TestRepositoryWatch(t *testing.T) {
+	old := watchInterval
+	watchInterval = 10 * time.Millisecond
+	defer func() { watchInterval = old }()
+
+	tmpDir := t.TempDir()
+	repos := map[string]Repository{
+		"store":  NewStore(tmpDir),
+		"memory": NewMemoryRepository(),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			first := testADR(1, "First")
+			if err := repo.Save(first); err != nil {
+				t.Fatalf("Save() error: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			events, err := repo.Watch(ctx)
+			if err != nil {
+				t.Fatalf("Watch() error: %v", err)
+			}
+
+			if err := repo.Save(testADR(2, "Second")); err != nil {
+				t.Fatalf("Save() error: %v", err)
+			}
+			got := collectEvents(t, events, 1)
+			if got[0] != (Event{Kind: EventCreated, Filename: "0002-second.md"}) {
+				t.Errorf("event = %v, want 0002-second.md created", got[0])
+			}
+
+			first.Title = "First, revised"
+			if err := repo.Save(first); err != nil {
+				t.Fatalf("Save() error: %v", err)
+			}
+			got = collectEvents(t, events, 1)
+			if got[0] != (Event{Kind: EventModified, Filename: "0001-first.md"}) {
+				t.Errorf("event = %v, want 0001-first.md modified", got[0])
+			}
+
+			if err := repo.Delete("0002-second.md"); err != nil {
+				t.Fatalf("Delete() error: %v", err)
+			}
+			got = collectEvents(t, events, 1)
+			if got[0] != (Event{Kind: EventRemoved, Filename: "0002-second.md"}) {
+				t.Errorf("event = %v, want 0002-second.md removed", got[0])
+			}
+
+			cancel()
+			for range events {
+			}
+		})
+	}
+}
+
+func TestStoreDelete(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	a := testADR(1, "First")
+	if err := store.Save(a); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if err := store.Delete(a.Filename); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, a.Filename)); !os.IsNotExist(err) {
+		t.Errorf("file still exists after Delete()")
+	}
+}
@@ -0,0 +1,86 @@
+package adr
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RetitleResult describes the files changed by Retitle
+type RetitleResult struct {
+	Number      int
+	OldTitle    string
+	OldFilename string
+	NewFilename string
+	Updated     []string // other ADRs whose links were rewritten, relative to the directory
+}
+
+// Retitle changes the title of an ADR: its heading is rewritten, the file
+// renamed to match the new title and every link to it from other ADRs
+// updated, all as one change. The ADR keeps its number.
+func (s *Store) Retitle(number int, title string) (*RetitleResult, error) {
+	title = strings.TrimSpace(title)
+	if title == "" || strings.ContainsAny(title, "\r\n") {
+		return nil, fmt.Errorf("invalid title %q", title)
+	}
+	if Slugify(title) == "" {
+		return nil, fmt.Errorf("title %q has no letters or digits to name the file after", title)
+	}
+
+	var result *RetitleResult
+	err := WithLocks(s.stores(), func() error {
+		a, err := FindByNumber(s, number)
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("ADR %s not found", s.Scheme.FormatNumber(number))
+		}
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(filepath.Join(s.Directory, a.Filename))
+		if err != nil {
+			return err
+		}
+
+		parts, _ := s.Scheme.ParseFilename(a.Filename)
+		newFilename := s.Scheme.filename(number, Slugify(title), parts.Date)
+		if newFilename != a.Filename {
+			if _, err := os.Stat(filepath.Join(s.Directory, newFilename)); err == nil {
+				return fmt.Errorf("%s already exists", newFilename)
+			}
+		}
+		result = &RetitleResult{Number: number, OldTitle: a.Title, OldFilename: a.Filename, NewFilename: newFilename}
+
+		changes := []fileChange{{
+			path:    filepath.Join(s.Directory, newFilename),
+			content: []byte(s.Scheme.retitleHeading(string(content), title)),
+		}}
+		if newFilename == a.Filename {
+			return applyChanges(changes)
+		}
+		changes = append(changes, fileChange{path: filepath.Join(s.Directory, a.Filename), remove: true})
+
+		relinks, updated, err := s.relink(a.Filename, newFilename, 0)
+		if err != nil {
+			return err
+		}
+		result.Updated = updated
+
+		return applyChanges(append(changes, relinks...))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// retitleHeading replaces the title in the first title line of content
+func (s Scheme) retitleHeading(content, title string) string {
+	return s.rewriteHeading(content, func(line string, m headingMatch) string {
+		return line[:m.title[0]] + title + line[m.title[1]:]
+	})
+}
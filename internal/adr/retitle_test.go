@@ -0,0 +1,163 @@
+package adr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRetitleHeading(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"standard", "# 42. Use Kafka\n\nDate: 2024-01-15\n", "# 42. Use Pulsar\n\nDate: 2024-01-15\n"},
+		{"no space", "#42.Use Kafka\n", "#42.Use Pulsar\n"},
+		{"only first heading", "# 42. Use Kafka\n# 43. Use Kafka\n", "# 42. Use Pulsar\n# 43. Use Kafka\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultScheme.retitleHeading(tt.input, "Use Pulsar"); got != tt.want {
+				t.Errorf("retitleHeading() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreRetitle(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	linking := NewADR(3, "Linking")
+	linking.StatusExtra = []string{"Amends [ADR-0002](0002-use-kafka.md)", "Clarifies [ADR-0001](0001-first.md)"}
+	for _, a := range []*ADR{NewADR(1, "First"), NewADR(2, "Use Kafka"), linking} {
+		if err := store.Save(a); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+
+	result, err := store.Retitle(2, "Use Pulsar for events")
+	if err != nil {
+		t.Fatalf("Retitle() error: %v", err)
+	}
+	if result.OldTitle != "Use Kafka" || result.NewFilename != "0002-use-pulsar-for-events.md" {
+		t.Errorf("Retitle() = %+v", result)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != "0003-linking.md" {
+		t.Errorf("Updated = %v, want [0003-linking.md]", result.Updated)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "0002-use-kafka.md")); !os.IsNotExist(err) {
+		t.Error("Retitle() did not remove the old file")
+	}
+
+	retitled, err := store.Load("0002-use-pulsar-for-events.md")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if retitled.Number != 2 || retitled.Title != "Use Pulsar for events" {
+		t.Errorf("heading = %d. %s, want 2. Use Pulsar for events", retitled.Number, retitled.Title)
+	}
+
+	updated, err := store.Load("0003-linking.md")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if updated.StatusExtra[0] != "Amends [ADR-0002](0002-use-pulsar-for-events.md)" {
+		t.Errorf("StatusExtra[0] = %q, want link to the new file", updated.StatusExtra[0])
+	}
+	if updated.StatusExtra[1] != "Clarifies [ADR-0001](0001-first.md)" {
+		t.Errorf("StatusExtra[1] = %q, link to another ADR must not change", updated.StatusExtra[1])
+	}
+}
+
+func TestStoreRetitleAcrossCollections(t *testing.T) {
+	root := t.TempDir()
+	payments := NewStore(filepath.Join(root, "payments", "adr"))
+	payments.Scheme = Scheme{Prefix: "PAY"}
+	platform := NewStore(filepath.Join(root, "platform", "adr"))
+	for _, s := range []*Store{payments, platform} {
+		if err := os.MkdirAll(s.Directory, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	payments.Linked = []*Store{platform}
+
+	// Links written by 'stamp link' between collections
+	linking := NewADR(1, "Linking")
+	linking.StatusExtra = []string{"Amends [PAY-0003](../../payments/adr/0003-use-kafka.md)"}
+	if err := platform.Save(linking); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if err := payments.Save(NewADR(3, "Use Kafka")); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	result, err := payments.Retitle(3, "Use Pulsar")
+	if err != nil {
+		t.Fatalf("Retitle() error: %v", err)
+	}
+	want := filepath.Join("..", "..", "platform", "adr", "0001-linking.md")
+	if len(result.Updated) != 1 || result.Updated[0] != filepath.ToSlash(want) {
+		t.Errorf("Updated = %v, want [%s]", result.Updated, want)
+	}
+
+	updated, err := platform.Load("0001-linking.md")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got := updated.StatusExtra[0]; got != "Amends [PAY-0003](../../payments/adr/0003-use-pulsar.md)" {
+		t.Errorf("StatusExtra[0] = %q, want the relative link to the new file", got)
+	}
+
+	// Renumbering rewrites the ID of the link as well
+	if _, err := payments.Renumber("0003-use-pulsar.md", 4); err != nil {
+		t.Fatalf("Renumber() error: %v", err)
+	}
+	updated, err = platform.Load("0001-linking.md")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got := updated.StatusExtra[0]; got != "Amends [PAY-0004](../../payments/adr/0004-use-pulsar.md)" {
+		t.Errorf("StatusExtra[0] = %q, want the renumbered link", got)
+	}
+}
+
+func TestStoreRetitleErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	for _, a := range []*ADR{NewADR(1, "First"), NewADR(2, "Second")} {
+		if err := store.Save(a); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+
+	if _, err := store.Retitle(3, "Missing"); err == nil {
+		t.Error("Retitle() expected error for a missing ADR")
+	}
+	if _, err := store.Retitle(1, "  "); err == nil {
+		t.Error("Retitle() expected error for an empty title")
+	}
+	if _, err := store.Retitle(1, "!!!"); err == nil {
+		t.Error("Retitle() expected error for a title without a slug")
+	}
+
+	// Changing only the capitalization keeps the file
+	result, err := store.Retitle(1, "FIRST")
+	if err != nil {
+		t.Fatalf("Retitle() error: %v", err)
+	}
+	if result.NewFilename != "0001-first.md" || len(result.Updated) != 0 {
+		t.Errorf("Retitle() = %+v, want the same file", result)
+	}
+	a, err := store.Load("0001-first.md")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if a.Title != "FIRST" {
+		t.Errorf("Title = %q, want FIRST", a.Title)
+	}
+}
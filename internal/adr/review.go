@@ -0,0 +1,145 @@
+package adr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// reviewSection is the heading of the section listing reviewers
+const reviewSection = "Reviews"
+
+// Verdict is a reviewer's sign-off on an ADR
+type Verdict string
+
+const (
+	VerdictPending  Verdict = "Pending"
+	VerdictApproved Verdict = "Approved"
+	VerdictRejected Verdict = "Rejected"
+)
+
+// Review is a reviewer asked to sign off an ADR, and their verdict
+type Review struct {
+	Reviewer string
+	Verdict  Verdict
+	Date     time.Time // when the review was requested, or the verdict given
+	Comment  string    // optional
+
+	date string // the date as written, kept when it doesn't parse
+}
+
+// RequestReview adds reviewers to the ADR, skipping those already asked, and
+// returns the ones added
+func (a *ADR) RequestReview(date time.Time, reviewers ...string) []string {
+	var added []string
+	for _, reviewer := range reviewers {
+		reviewer = strings.Join(strings.Fields(reviewer), " ")
+		if reviewer == "" || a.FindReview(reviewer) != nil {
+			continue
+		}
+		a.Reviews = append(a.Reviews, Review{Reviewer: reviewer, Verdict: VerdictPending, Date: date})
+		added = append(added, reviewer)
+	}
+	return added
+}
+
+// FindReview returns the review of reviewer, compared case-insensitively, or
+// nil if they weren't asked
+func (a *ADR) FindReview(reviewer string) *Review {
+	for i := range a.Reviews {
+		if strings.EqualFold(a.Reviews[i].Reviewer, reviewer) {
+			return &a.Reviews[i]
+		}
+	}
+	return nil
+}
+
+// SignOff records the verdict of a requested reviewer, replacing any earlier
+// one
+func (a *ADR) SignOff(reviewer string, verdict Verdict, date time.Time, comment string) error {
+	r := a.FindReview(reviewer)
+	if r == nil {
+		return fmt.Errorf("%s is not a reviewer", reviewer)
+	}
+	r.Verdict = verdict
+	r.Date = date
+	r.date = ""
+	r.Comment = strings.Join(strings.Fields(comment), " ")
+	return nil
+}
+
+// Reviewers returns the names of the reviewers with verdict
+func (a *ADR) Reviewers(verdict Verdict) []string {
+	var names []string
+	for _, r := range a.Reviews {
+		if r.Verdict == verdict {
+			names = append(names, r.Reviewer)
+		}
+	}
+	return names
+}
+
+// Approved reports whether quorum reviewers approved the ADR, or all of
+// them when quorum is 0. A standing rejection blocks approval until the
+// reviewer changes their verdict.
+func (a *ADR) Approved(quorum int) bool {
+	if len(a.Reviews) == 0 || len(a.Reviewers(VerdictRejected)) > 0 {
+		return false
+	}
+	if quorum <= 0 {
+		quorum = len(a.Reviews)
+	}
+	return len(a.Reviewers(VerdictApproved)) >= quorum
+}
+
+// writeReviews writes the review section as a Markdown table
+func writeReviews(sb *strings.Builder, reviews []Review) {
+	sb.WriteString("## " + reviewSection + "\n\n")
+	sb.WriteString("| Reviewer | Verdict | Date | Comment |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+	for _, r := range reviews {
+		date := r.date
+		if !r.Date.IsZero() {
+			date = r.Date.Format("2006-01-02")
+		}
+		cells := []string{r.Reviewer, string(r.Verdict), date, r.Comment}
+		for i, cell := range cells {
+			cells[i] = escapeCell(cell)
+		}
+		fmt.Fprintf(sb, "| %s |\n", strings.Join(cells, " | "))
+	}
+	sb.WriteString("\n")
+}
+
+// parseReviews reads the rows of the review table, like parseHistory
+func parseReviews(text string) []Review {
+	var reviews []Review
+	for _, line := range strings.Split(text, "\n") {
+		cells := splitRow(line)
+		if len(cells) < 2 || cells[0] == "Reviewer" || strings.Trim(cells[0], "-: ") == "" {
+			continue
+		}
+		for len(cells) < 4 {
+			cells = append(cells, "")
+		}
+
+		r := Review{Reviewer: cells[0], Verdict: parseVerdict(cells[1]), Comment: cells[3]}
+		if t, err := time.Parse("2006-01-02", cells[2]); err == nil {
+			r.Date = t
+		} else {
+			r.date = cells[2]
+		}
+		reviews = append(reviews, r)
+	}
+	return reviews
+}
+
+// parseVerdict normalizes a verdict, keeping unknown ones as written
+func parseVerdict(s string) Verdict {
+	for _, v := range []Verdict{VerdictPending, VerdictApproved, VerdictRejected} {
+		if strings.EqualFold(s, string(v)) {
+			return v
+		}
+	}
+	return Verdict(s)
+}
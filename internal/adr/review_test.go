@@ -0,0 +1,82 @@
+package adr
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReviewWorkflow(t *testing.T) {
+	a := NewADR(4, "Use Kafka")
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	added := a.RequestReview(day, "alice", " bob ", "Alice", "")
+	if !slices.Equal(added, []string{"alice", "bob"}) {
+		t.Errorf("RequestReview() = %v, want [alice bob]", added)
+	}
+	if a.Approved(0) {
+		t.Error("Approved() before any verdict = true")
+	}
+
+	if err := a.SignOff("carol", VerdictApproved, day, ""); err == nil {
+		t.Error("SignOff() by someone not asked expected an error")
+	}
+	if err := a.SignOff("ALICE", VerdictApproved, day.AddDate(0, 0, 1), "Looks good"); err != nil {
+		t.Fatalf("SignOff() error: %v", err)
+	}
+	if !a.Approved(1) || a.Approved(0) {
+		t.Errorf("Approved() with 1 of 2 approvals: quorum 1 = %v, all = %v", a.Approved(1), a.Approved(0))
+	}
+
+	if err := a.SignOff("bob", VerdictRejected, day.AddDate(0, 0, 2), ""); err != nil {
+		t.Fatalf("SignOff() error: %v", err)
+	}
+	if a.Approved(1) {
+		t.Error("Approved() with a standing rejection = true, want the rejection to block the quorum")
+	}
+	if err := a.SignOff("bob", VerdictApproved, day.AddDate(0, 0, 3), "After the changes"); err != nil {
+		t.Fatalf("SignOff() error: %v", err)
+	}
+	if !a.Approved(0) {
+		t.Error("Approved() with every reviewer approving = false")
+	}
+	if got := a.Reviewers(VerdictRejected); len(got) != 0 {
+		t.Errorf("Reviewers(Rejected) = %v, want none after bob changed their verdict", got)
+	}
+}
+
+func TestReviewsRoundTrip(t *testing.T) {
+	a := NewADR(4, "Use Kafka")
+	a.Date = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	a.SetStatus(StatusProposed, a.Date, "", "")
+	a.RequestReview(a.Date, "alice", "bob")
+	if err := a.SignOff("alice", VerdictRejected, a.Date.AddDate(0, 0, 1), "Needs a cost | benefit analysis"); err != nil {
+		t.Fatal(err)
+	}
+
+	content := a.ToMarkdown()
+	for _, want := range []string{
+		"## Reviews\n\n| Reviewer | Verdict | Date | Comment |\n",
+		"| alice | Rejected | 2026-10-02 | Needs a cost \\| benefit analysis |\n",
+		"| bob | Pending | 2026-10-01 |  |\n\n## Context",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("ToMarkdown() missing %q in:\n%s", want, content)
+		}
+	}
+	if strings.Index(content, "## Status history") > strings.Index(content, "## Reviews") {
+		t.Error("Reviews should follow the status history")
+	}
+
+	parsed, err := ParseMarkdown(content)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error: %v", err)
+	}
+	if len(parsed.Reviews) != 2 || parsed.Reviews[0] != a.Reviews[0] || parsed.Reviews[1] != a.Reviews[1] {
+		t.Errorf("Reviews = %+v, want %+v", parsed.Reviews, a.Reviews)
+	}
+	if parsed.ToMarkdown() != content {
+		t.Errorf("round trip changed the content:\n%s", parsed.ToMarkdown())
+	}
+}
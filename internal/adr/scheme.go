@@ -0,0 +1,355 @@
+package adr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// NumberingSequential numbers ADRs 1, 2, 3, ...
+	NumberingSequential = "sequential"
+	// NumberingDate numbers ADRs by creation date and a two-digit counter,
+	// like 2024011501, so ADRs created on different branches on different
+	// days never collide
+	NumberingDate = "date"
+)
+
+// Scheme describes how ADRs are numbered and how their files are named
+type Scheme struct {
+	// Width is the minimum number of digits of a formatted number
+	Width int
+	// Filename is the filename template. It must contain {number} and
+	// {slug}, may contain {date} (YYYY-MM-DD) and must end in ".md".
+	Filename string
+	// Numbering is NumberingSequential or NumberingDate
+	Numbering string
+	// Prefix starts the ID of an ADR, as in "ADR-0001"
+	Prefix string
+	// Heading is the title line template, after the "# ". It must contain
+	// {title} and either {number} (the bare number) or {id} (the prefixed,
+	// padded ID) exactly once.
+	Heading string
+}
+
+// DefaultScheme names ADRs like "0001-use-postgres.md", titled "# 1. Use Postgres"
+var DefaultScheme = Scheme{
+	Width:     4,
+	Filename:  "{number}-{slug}.md",
+	Numbering: NumberingSequential,
+	Prefix:    "ADR",
+	Heading:   "{number}. {title}",
+}
+
+// withDefaults fills the unset fields of s from DefaultScheme, so the zero
+// Scheme behaves like the default one
+func (s Scheme) withDefaults() Scheme {
+	if s.Width == 0 {
+		s.Width = DefaultScheme.Width
+	}
+	if s.Filename == "" {
+		s.Filename = DefaultScheme.Filename
+	}
+	if s.Numbering == "" {
+		s.Numbering = DefaultScheme.Numbering
+	}
+	if s.Prefix == "" {
+		s.Prefix = DefaultScheme.Prefix
+	}
+	if s.Heading == "" {
+		s.Heading = DefaultScheme.Heading
+	}
+	return s
+}
+
+// Validate checks the filename template, numbering and heading template
+func (s Scheme) Validate() error {
+	s = s.withDefaults()
+	if s.Width < 1 || s.Width > 12 {
+		return fmt.Errorf("invalid number width %d (expected 1 to 12)", s.Width)
+	}
+	for _, placeholder := range []string{"{number}", "{slug}"} {
+		if strings.Count(s.Filename, placeholder) != 1 {
+			return fmt.Errorf("filename template %q must contain %s exactly once", s.Filename, placeholder)
+		}
+	}
+	if strings.Count(s.Filename, "{date}") > 1 {
+		return fmt.Errorf("filename template %q contains {date} more than once", s.Filename)
+	}
+	if !strings.HasSuffix(s.Filename, ".md") || strings.ContainsAny(s.Filename, `/\`) {
+		return fmt.Errorf("filename template %q must be a file name ending in .md", s.Filename)
+	}
+	if s.Numbering != NumberingSequential && s.Numbering != NumberingDate {
+		return fmt.Errorf("invalid numbering %q (expected %s or %s)", s.Numbering, NumberingSequential, NumberingDate)
+	}
+	if strings.Count(s.Heading, "{title}") != 1 {
+		return fmt.Errorf("heading template %q must contain {title} exactly once", s.Heading)
+	}
+	if strings.Count(s.Heading, "{number}")+strings.Count(s.Heading, "{id}") != 1 {
+		return fmt.Errorf("heading template %q must contain either {number} or {id} exactly once", s.Heading)
+	}
+	if strings.ContainsAny(s.Heading, "\r\n") {
+		return fmt.Errorf("heading template %q must be a single line", s.Heading)
+	}
+	return nil
+}
+
+// FormatNumber formats an ADR number with the scheme's width
+func (s Scheme) FormatNumber(number int) string {
+	s = s.withDefaults()
+	return fmt.Sprintf("%0*d", s.Width, number)
+}
+
+// ID formats the ID of an ADR, like "ADR-0001"
+func (s Scheme) ID(number int) string {
+	s = s.withDefaults()
+	return s.Prefix + "-" + s.FormatNumber(number)
+}
+
+// FormatHeading returns the title line of an ADR, without a trailing newline
+func (s Scheme) FormatHeading(number int, title string) string {
+	s = s.withDefaults()
+	return "# " + strings.NewReplacer(
+		"{number}", strconv.Itoa(number),
+		"{id}", s.ID(number),
+		"{title}", title,
+	).Replace(s.Heading)
+}
+
+// FormatFilename returns the filename of an ADR
+func (s Scheme) FormatFilename(number int, title string, date time.Time) string {
+	return s.filename(number, Slugify(title), date.Format("2006-01-02"))
+}
+
+func (s Scheme) filename(number int, slug, date string) string {
+	s = s.withDefaults()
+	return strings.NewReplacer(
+		"{number}", s.FormatNumber(number),
+		"{slug}", slug,
+		"{date}", date,
+	).Replace(s.Filename)
+}
+
+// FilenameParts are the fields ParseFilename extracts from a filename
+type FilenameParts struct {
+	Number int
+	Slug   string
+	Date   string
+}
+
+var (
+	patternsMu sync.Mutex
+	patterns   = make(map[string]*regexp.Regexp)
+)
+
+// pattern compiles the filename template into a regular expression
+func (s Scheme) pattern() *regexp.Regexp {
+	s = s.withDefaults()
+
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+
+	key := "filename:" + s.Filename
+	if re, ok := patterns[key]; ok {
+		return re
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	rest := s.Filename
+	for rest != "" {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			sb.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		sb.WriteString(regexp.QuoteMeta(rest[:start]))
+		rest = rest[start:]
+		switch {
+		case strings.HasPrefix(rest, "{number}"):
+			// Width only applies when formatting, so files keep matching
+			// when it changes
+			sb.WriteString(`(?P<number>\d+)`)
+			rest = rest[len("{number}"):]
+		case strings.HasPrefix(rest, "{slug}"):
+			sb.WriteString(`(?P<slug>.+)`)
+			rest = rest[len("{slug}"):]
+		case strings.HasPrefix(rest, "{date}"):
+			sb.WriteString(`(?P<date>\d{4}-\d{2}-\d{2})`)
+			rest = rest[len("{date}"):]
+		default:
+			sb.WriteString(regexp.QuoteMeta("{"))
+			rest = rest[1:]
+		}
+	}
+	sb.WriteString("$")
+
+	re := regexp.MustCompile(sb.String())
+	patterns[key] = re
+	return re
+}
+
+// headingPattern compiles the heading template into a regular expression
+// matching a title line. The number is captured as "number", or as "id"
+// when the template uses the padded ID; spaces match any amount of space.
+func (s Scheme) headingPattern() *regexp.Regexp {
+	s = s.withDefaults()
+
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+
+	key := "heading:" + s.Prefix + ":" + s.Heading
+	if re, ok := patterns[key]; ok {
+		return re
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`^#\s*`)
+	rest := s.Heading
+	for rest != "" {
+		start := strings.IndexAny(rest, "{ ")
+		if start < 0 {
+			sb.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		sb.WriteString(regexp.QuoteMeta(rest[:start]))
+		rest = rest[start:]
+		switch {
+		case rest[0] == ' ':
+			sb.WriteString(`\s*`)
+			rest = strings.TrimLeft(rest, " ")
+		case strings.HasPrefix(rest, "{number}"):
+			sb.WriteString(`(?P<number>\d+)`)
+			rest = rest[len("{number}"):]
+		case strings.HasPrefix(rest, "{id}"):
+			sb.WriteString(`(?i:` + regexp.QuoteMeta(s.Prefix+"-") + `)(?P<id>\d+)`)
+			rest = rest[len("{id}"):]
+		case strings.HasPrefix(rest, "{title}"):
+			sb.WriteString(`(?P<title>.*\S)`)
+			rest = rest[len("{title}"):]
+		default:
+			sb.WriteString(regexp.QuoteMeta("{"))
+			rest = rest[1:]
+		}
+	}
+	sb.WriteString(`\s*$`)
+
+	re := regexp.MustCompile(sb.String())
+	patterns[key] = re
+	return re
+}
+
+// headingMatch locates the number and title of a title line, as [start, end)
+// offsets into the line
+type headingMatch struct {
+	number, title [2]int
+	padded        bool // the number is written as a padded ID
+}
+
+// matchHeading matches line against the heading template, then against the
+// default "# N. Title" so files written before the template changed still
+// parse
+func (s Scheme) matchHeading(line string) (headingMatch, bool) {
+	for _, re := range []*regexp.Regexp{s.headingPattern(), DefaultScheme.headingPattern()} {
+		loc := re.FindStringSubmatchIndex(line)
+		if loc == nil {
+			continue
+		}
+		var m headingMatch
+		for i, group := range re.SubexpNames() {
+			switch group {
+			case "number", "id":
+				m.number = [2]int{loc[2*i], loc[2*i+1]}
+				m.padded = group == "id"
+			case "title":
+				m.title = [2]int{loc[2*i], loc[2*i+1]}
+			}
+		}
+		return m, true
+	}
+	return headingMatch{}, false
+}
+
+// ParseHeading extracts the number and title of a title line, reporting
+// whether line is one
+func (s Scheme) ParseHeading(line string) (int, string, bool) {
+	m, ok := s.matchHeading(line)
+	if !ok {
+		return 0, "", false
+	}
+	number, err := strconv.Atoi(line[m.number[0]:m.number[1]])
+	if err != nil {
+		return 0, "", false
+	}
+	return number, line[m.title[0]:m.title[1]], true
+}
+
+// ParseFilename extracts the number and slug of an ADR filename, reporting
+// whether the name matches the scheme at all
+func (s Scheme) ParseFilename(name string) (FilenameParts, bool) {
+	re := s.pattern()
+	match := re.FindStringSubmatch(name)
+	if match == nil {
+		return FilenameParts{}, false
+	}
+
+	var parts FilenameParts
+	for i, group := range re.SubexpNames() {
+		switch group {
+		case "number":
+			n, err := strconv.Atoi(match[i])
+			if err != nil {
+				return FilenameParts{}, false
+			}
+			parts.Number = n
+		case "slug":
+			parts.Slug = match[i]
+		case "date":
+			parts.Date = match[i]
+		}
+	}
+	return parts, true
+}
+
+// Matches reports whether name is an ADR filename under the scheme
+func (s Scheme) Matches(name string) bool {
+	_, ok := s.ParseFilename(name)
+	return ok
+}
+
+// Canonical reports whether name is an ADR filename exactly as the scheme
+// would write it, its number padded to the width
+func (s Scheme) Canonical(name string) bool {
+	parts, ok := s.ParseFilename(name)
+	return ok && s.filename(parts.Number, parts.Slug, parts.Date) == name
+}
+
+// NextNumber returns the number for a new ADR created at now, given the
+// existing filenames. With date numbering, it fails once the 99 numbers of
+// the day are used up.
+func (s Scheme) NextNumber(names []string, now time.Time) (int, error) {
+	maxNum := 0
+	for _, name := range names {
+		if parts, ok := s.ParseFilename(name); ok && parts.Number > maxNum {
+			maxNum = parts.Number
+		}
+	}
+
+	if s.withDefaults().Numbering != NumberingDate {
+		return maxNum + 1, nil
+	}
+
+	// Date numbers are YYYYMMDDNN; continue the counter of today if any
+	y, m, d := now.Date()
+	today := (y*10000 + int(m)*100 + d) * 100
+	switch {
+	case maxNum == today+99:
+		return 0, fmt.Errorf("all 99 ADR numbers of %s are used", now.Format("2006-01-02"))
+	case maxNum > today && maxNum < today+99:
+		return maxNum + 1, nil
+	}
+	return today + 1, nil
+}
@@ -0,0 +1,206 @@
+package adr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSchemeValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		scheme  Scheme
+		wantErr bool
+	}{
+		{"zero value", Scheme{}, false},
+		{"default", DefaultScheme, false},
+		{"date in filename", Scheme{Filename: "{date}-{number}-{slug}.md"}, false},
+		{"date numbering", Scheme{Width: 10, Numbering: NumberingDate}, false},
+		{"missing number", Scheme{Filename: "{slug}.md"}, true},
+		{"missing slug", Scheme{Filename: "{number}.md"}, true},
+		{"number twice", Scheme{Filename: "{number}-{number}-{slug}.md"}, true},
+		{"not markdown", Scheme{Filename: "{number}-{slug}.txt"}, true},
+		{"subdirectory", Scheme{Filename: "{number}/{slug}.md"}, true},
+		{"width too large", Scheme{Width: 13}, true},
+		{"unknown numbering", Scheme{Numbering: "ulid"}, true},
+		{"id heading", Scheme{Heading: "{id}: {title}"}, false},
+		{"heading without title", Scheme{Heading: "{id}"}, true},
+		{"heading without number", Scheme{Heading: "{title}"}, true},
+		{"heading with number and id", Scheme{Heading: "{id} {number}. {title}"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.scheme.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchemeFilenames(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		scheme Scheme
+		want   string
+	}{
+		{"default", Scheme{}, "0007-use-postgres.md"},
+		{"narrow", Scheme{Width: 3}, "007-use-postgres.md"},
+		{"wide", Scheme{Width: 6}, "000007-use-postgres.md"},
+		{"date first", Scheme{Filename: "{date}-{number}-{slug}.md"}, "2024-01-15-0007-use-postgres.md"},
+		{"prefixed", Scheme{Filename: "adr_{number}_{slug}.md"}, "adr_0007_use-postgres.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.scheme.FormatFilename(7, "Use Postgres", date)
+			if got != tt.want {
+				t.Fatalf("FormatFilename() = %q, want %q", got, tt.want)
+			}
+
+			parts, ok := tt.scheme.ParseFilename(got)
+			if !ok {
+				t.Fatalf("ParseFilename(%q) did not match", got)
+			}
+			if parts.Number != 7 || parts.Slug != "use-postgres" {
+				t.Errorf("ParseFilename(%q) = %+v", got, parts)
+			}
+
+			// Files named with the default scheme don't match other templates
+			if tt.scheme.Filename != "" && tt.scheme.Matches("0007-use-postgres.md") {
+				t.Errorf("Matches(0007-use-postgres.md) = true for template %q", tt.scheme.Filename)
+			}
+		})
+	}
+}
+
+func TestSchemeNextNumber(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		scheme  Scheme
+		names   []string
+		want    int
+		wantErr bool
+	}{
+		{"sequential empty", Scheme{}, nil, 1, false},
+		{"sequential", Scheme{}, []string{"0001-a.md", "0003-b.md", "README.md"}, 4, false},
+		{"date first of the day", Scheme{Numbering: NumberingDate}, []string{"2024011202-a.md"}, 2024011501, false},
+		{"date same day", Scheme{Numbering: NumberingDate}, []string{"2024011501-a.md", "2024011502-b.md"}, 2024011503, false},
+		{"date last of the day", Scheme{Numbering: NumberingDate}, []string{"2024011598-a.md"}, 2024011599, false},
+		{"date day used up", Scheme{Numbering: NumberingDate}, []string{"2024011599-a.md"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.scheme.NextNumber(tt.names, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NextNumber() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NextNumber() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemeWidthOnlyFormats(t *testing.T) {
+	// Files named before the width was raised still belong to the log
+	wide := Scheme{Width: 5}
+	parts, ok := wide.ParseFilename("0001-use-postgres.md")
+	if !ok || parts.Number != 1 {
+		t.Fatalf("ParseFilename(0001-use-postgres.md) = %+v, %v with width 5", parts, ok)
+	}
+	if got := wide.FormatFilename(2, "Use Kafka", time.Time{}); got != "00002-use-kafka.md" {
+		t.Errorf("FormatFilename() = %q, want 00002-use-kafka.md", got)
+	}
+	if wide.Canonical("0001-use-postgres.md") || !wide.Canonical("00001-use-postgres.md") {
+		t.Error("Canonical() should only accept numbers padded to the width")
+	}
+}
+
+func TestSchemeHeadings(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme Scheme
+		want   string
+	}{
+		{"default", Scheme{}, "# 7. Use Postgres"},
+		{"id", Scheme{Heading: "{id}. {title}"}, "# ADR-0007. Use Postgres"},
+		{"prefixed id", Scheme{Prefix: "SEC", Width: 3, Heading: "{id}: {title}"}, "# SEC-007: Use Postgres"},
+		{"title first", Scheme{Heading: "{title} ({number})"}, "# Use Postgres (7)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.scheme.FormatHeading(7, "Use Postgres")
+			if got != tt.want {
+				t.Fatalf("FormatHeading() = %q, want %q", got, tt.want)
+			}
+
+			number, title, ok := tt.scheme.ParseHeading(got)
+			if !ok || number != 7 || title != "Use Postgres" {
+				t.Errorf("ParseHeading(%q) = %d, %q, %v", got, number, title, ok)
+			}
+
+			// Headings written before the template changed still parse
+			if number, _, ok := tt.scheme.ParseHeading("# 3. Older"); !ok || number != 3 {
+				t.Errorf("ParseHeading(# 3. Older) = %d, %v", number, ok)
+			}
+		})
+	}
+}
+
+func TestSchemeRewriteHeadings(t *testing.T) {
+	scheme := Scheme{Prefix: "SEC", Heading: "{id}. {title}"}
+	content := "# SEC-0042. Use Kafka\n\nDate: 2024-01-15\n"
+
+	if got, want := scheme.renumberHeading(content, 45), "# SEC-0045. Use Kafka\n\nDate: 2024-01-15\n"; got != want {
+		t.Errorf("renumberHeading() = %q, want %q", got, want)
+	}
+	if got, want := scheme.retitleHeading(content, "Use Pulsar"), "# SEC-0042. Use Pulsar\n\nDate: 2024-01-15\n"; got != want {
+		t.Errorf("retitleHeading() = %q, want %q", got, want)
+	}
+}
+
+func TestStoreCustomScheme(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	store := NewStore(tmpDir)
+	store.Scheme = Scheme{Width: 3, Filename: "{date}-{number}-{slug}.md"}
+
+	a := &ADR{Title: "Use Postgres", Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Status: StatusProposed}
+	if err := store.Create(a); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	want := "2024-01-15-001-use-postgres.md"
+	if a.Filename != want {
+		t.Errorf("Filename = %q, want %q", a.Filename, want)
+	}
+
+	// Files that don't follow the scheme are not ADRs
+	if err := os.WriteFile(filepath.Join(tmpDir, "0005-other.md"), []byte("# 5. Other\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	adrs, _, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(adrs) != 1 || adrs[0].Number != 1 {
+		t.Errorf("List() = %d ADRs, want only the ADR created with the scheme", len(adrs))
+	}
+
+	next, err := store.NextNumber()
+	if err != nil {
+		t.Fatalf("NextNumber() error: %v", err)
+	}
+	if next != 2 {
+		t.Errorf("NextNumber() = %d, want 2", next)
+	}
+}
@@ -0,0 +1,57 @@
+package adr
+
+import "time"
+
+// StaleReason is why an ADR should be revisited
+type StaleReason string
+
+const (
+	// StaleInactive is a draft or proposed ADR left without activity
+	StaleInactive StaleReason = "inactive"
+	// StaleReviewDue is an accepted ADR that reached its review date
+	StaleReviewDue StaleReason = "review-due"
+)
+
+// LastActivity returns the latest date the ADR was created, changed status or
+// reviewed
+func (a *ADR) LastActivity() time.Time {
+	last := a.Date
+	if changed := a.LastChanged(); changed.After(last) {
+		last = changed
+	}
+	for _, r := range a.Reviews {
+		if r.Date.After(last) {
+			last = r.Date
+		}
+	}
+	return last
+}
+
+// Stale reports whether the ADR should be revisited on the day of now: a
+// draft or proposed ADR without activity for more than days days, or an
+// accepted ADR whose review date has come
+func (a *ADR) Stale(now time.Time, days int) (StaleReason, bool) {
+	today := civilDate(now)
+	switch a.Status {
+	case StatusDraft, StatusProposed:
+		if last := a.LastActivity(); !last.IsZero() && DaysBetween(last, today) > days {
+			return StaleInactive, true
+		}
+	case StatusAccepted:
+		if !a.ReviewBy.IsZero() && !today.Before(civilDate(a.ReviewBy)) {
+			return StaleReviewDue, true
+		}
+	}
+	return "", false
+}
+
+// DaysBetween returns the number of calendar days from one date to another
+func DaysBetween(from, to time.Time) int {
+	return int(civilDate(to).Sub(civilDate(from)).Hours() / 24)
+}
+
+// civilDate returns the day of t as midnight UTC, so that dates parsed from
+// ADRs and the local time compare by day
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,74 @@
+package adr
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStale(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	// Late in the local day, to check that ADR dates compare by day
+	now := time.Date(2026, 10, 18, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600))
+
+	draft := &ADR{Number: 1, Date: day("2026-09-01"), Status: StatusDraft}
+	proposed := &ADR{Number: 2, Date: day("2026-08-01"), Status: StatusProposed,
+		Reviews: []Review{{Reviewer: "alice", Verdict: VerdictPending, Date: day("2026-10-01")}}}
+	accepted := &ADR{Number: 3, Date: day("2025-01-01"), Status: StatusAccepted, ReviewBy: day("2026-10-18")}
+	later := &ADR{Number: 4, Date: day("2025-01-01"), Status: StatusAccepted, ReviewBy: day("2026-10-19")}
+	superseded := &ADR{Number: 5, Date: day("2020-01-01"), Status: StatusSuperseded, ReviewBy: day("2021-01-01")}
+
+	tests := []struct {
+		adr  *ADR
+		days int
+		want StaleReason
+	}{
+		{draft, 46, StaleInactive},
+		{draft, 47, ""},
+		{proposed, 16, StaleInactive},
+		{proposed, 17, ""}, // the review request is the latest activity
+		{accepted, 30, StaleReviewDue},
+		{later, 30, ""},
+		{superseded, 30, ""},
+	}
+	for _, tt := range tests {
+		got, stale := tt.adr.Stale(now, tt.days)
+		if got != tt.want || stale != (tt.want != "") {
+			t.Errorf("ADR %d Stale(%d days) = %q, %v, want %q", tt.adr.Number, tt.days, got, stale, tt.want)
+		}
+	}
+}
+
+func TestReviewByRoundTrip(t *testing.T) {
+	a := NewADR(1, "Use Kafka")
+	a.Date = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	a.Author = "Jane Doe"
+	a.ReviewBy = time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	content := a.ToMarkdown()
+	if !strings.Contains(content, "Author: Jane Doe\n\nReview by: 2027-04-01\n\n## Status") {
+		t.Errorf("ToMarkdown() missing the review date:\n%s", content)
+	}
+	parsed, err := ParseMarkdown(content)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error: %v", err)
+	}
+	if !parsed.ReviewBy.Equal(a.ReviewBy) {
+		t.Errorf("ReviewBy = %v, want %v", parsed.ReviewBy, a.ReviewBy)
+	}
+
+	// Expires is another name for the same date
+	parsed, err = ParseMarkdown("# 1. Use Kafka\n\nExpires: 2027-04-01\n\n## Status\n\nAccepted\n")
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error: %v", err)
+	}
+	if !parsed.ReviewBy.Equal(a.ReviewBy) {
+		t.Errorf("ReviewBy from Expires = %v, want %v", parsed.ReviewBy, a.ReviewBy)
+	}
+}
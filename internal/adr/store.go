@@ -1,125 +1,386 @@
 package adr
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
-	"sort"
-	"strconv"
+	"strings"
+	"time"
 )
 
 type Store struct {
 	Directory string
+	// Scheme names and numbers the ADR files; the zero value is DefaultScheme
+	Scheme Scheme
+	// Linked are the other stores whose ADRs may link to this one's, such as
+	// the other collections of a monorepo. Retitle and Renumber rewrite
+	// their links too.
+	Linked []*Store
 }
 
 func NewStore(directory string) *Store {
-	return &Store{Directory: directory}
+	return &Store{Directory: directory, Scheme: DefaultScheme}
 }
 
-var filenameRegex = regexp.MustCompile(`^(\d{4})-(.+)\.md$`)
-
-func (s *Store) List() ([]*ADR, error) {
+// names returns the names of the regular files in the ADR directory
+func (s *Store) names() ([]string, error) {
 	entries, err := os.ReadDir(s.Directory)
 	if err != nil {
 		return nil, err
 	}
 
-	var adrs []*ADR
+	var names []string
 	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
 		}
+	}
+	return names, nil
+}
 
-		if !filenameRegex.MatchString(entry.Name()) {
-			continue
-		}
+// List loads every ADR in the store, sorted by number. Files that look like
+// ADRs but cannot be read or parsed are skipped and reported as diagnostics,
+// as are numbers shared by several files.
+func (s *Store) List() ([]*ADR, []*Diagnostic, error) {
+	names, err := s.names()
+	if err != nil {
+		return nil, nil, err
+	}
 
-		adr, err := s.Load(entry.Name())
-		if err != nil {
-			continue
-		}
+	adrs, diagnostics := listADRs(s.Scheme, names, s.Load)
+	return adrs, diagnostics, nil
+}
 
-		adrs = append(adrs, adr)
-	}
+// Collision is a number used by more than one ADR file, typically the result
+// of merging branches that each created a new ADR
+type Collision struct {
+	Number    int
+	Filenames []string
+}
 
-	sort.Slice(adrs, func(i, j int) bool {
-		return adrs[i].Number < adrs[j].Number
-	})
+// DuplicateNumberError is returned when an ADR number is ambiguous
+type DuplicateNumberError struct {
+	Collision
+	// Scheme formats the number in the message
+	Scheme Scheme
+}
 
-	return adrs, nil
+func (e *DuplicateNumberError) Error() string {
+	return fmt.Sprintf("ADR %s is used by %d files: %s (run 'stamp renumber %s' to resolve)",
+		e.Scheme.FormatNumber(e.Number), len(e.Filenames), strings.Join(e.Filenames, ", "), e.Filenames[len(e.Filenames)-1])
 }
 
-func (s *Store) Load(filename string) (*ADR, error) {
-	path := filepath.Join(s.Directory, filename)
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// Collisions returns the numbers shared by more than one ADR, in ascending
+// order. adrs must be sorted as returned by List.
+func Collisions(adrs []*ADR) []Collision {
+	var collisions []Collision
+	for i := 0; i < len(adrs); {
+		j := i + 1
+		for j < len(adrs) && adrs[j].Number == adrs[i].Number {
+			j++
+		}
+		if j-i > 1 {
+			c := Collision{Number: adrs[i].Number}
+			for _, a := range adrs[i:j] {
+				c.Filenames = append(c.Filenames, a.Filename)
+			}
+			collisions = append(collisions, c)
+		}
+		i = j
 	}
+	return collisions
+}
 
-	adr, err := ParseMarkdown(string(content))
+func (s *Store) Load(filename string) (*ADR, error) {
+	content, err := os.ReadFile(filepath.Join(s.Directory, filename))
 	if err != nil {
 		return nil, err
 	}
-
-	adr.Filename = filename
-	return adr, nil
+	return parseADR(s.Scheme, filename, content)
 }
 
+// Save writes an ADR to disk. The file is replaced atomically, so readers
+// never see a partially written ADR.
 func (s *Store) Save(adr *ADR) error {
 	if adr.Filename == "" {
-		adr.Filename = FormatFilename(adr.Number, adr.Title)
+		adr.Filename = s.Scheme.FormatFilename(adr.Number, adr.Title, adr.Date)
 	}
 
 	path := filepath.Join(s.Directory, adr.Filename)
-	content := adr.ToMarkdown()
+	content := adr.FormatMarkdown(s.Scheme)
 
-	return os.WriteFile(path, []byte(content), 0644)
+	return writeFileAtomic(path, []byte(content))
 }
 
-func (s *Store) NextNumber() (int, error) {
-	entries, err := os.ReadDir(s.Directory)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return 1, nil
+// SaveAll writes several ADRs as one change: if any of them cannot be
+// written, the ones already written are restored to their previous content.
+func (s *Store) SaveAll(adrs ...*ADR) error {
+	entries := make([]StoredADR, len(adrs))
+	for i, a := range adrs {
+		entries[i] = StoredADR{Store: s, ADR: a}
+	}
+	return SaveAcross(entries...)
+}
+
+// StoredADR is an ADR together with the store it is saved in
+type StoredADR struct {
+	Store *Store
+	ADR   *ADR
+}
+
+// SaveAcross writes ADRs that live in different stores, such as two
+// collections of a monorepo, as one change like SaveAll
+func SaveAcross(entries ...StoredADR) error {
+	changes := make([]fileChange, len(entries))
+	for i, e := range entries {
+		if e.ADR.Filename == "" {
+			e.ADR.Filename = e.Store.Scheme.FormatFilename(e.ADR.Number, e.ADR.Title, e.ADR.Date)
+		}
+		changes[i] = fileChange{
+			path:    filepath.Join(e.Store.Directory, e.ADR.Filename),
+			content: []byte(e.ADR.FormatMarkdown(e.Store.Scheme)),
 		}
-		return 0, err
 	}
 
-	maxNum := 0
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+	return applyChanges(changes)
+}
+
+// fileChange is a single file operation applied by applyChanges
+type fileChange struct {
+	path    string
+	content []byte
+	remove  bool
+}
+
+// rename moves staged files into place; tests replace it to fail midway
+var rename = os.Rename
+
+// applyChanges applies several file changes as one unit. New content is
+// staged in temporary files before any file is touched, and if a step fails
+// the files already changed are restored.
+func applyChanges(changes []fileChange) error {
+	type pending struct {
+		fileChange
+		tmp      string
+		original []byte
+		existed  bool
+	}
+
+	var staged []pending
+	cleanup := func() {
+		for _, p := range staged {
+			if p.tmp != "" {
+				_ = os.Remove(p.tmp)
+			}
 		}
+	}
 
-		match := filenameRegex.FindStringSubmatch(entry.Name())
-		if match == nil {
-			continue
+	for _, c := range changes {
+		p := pending{fileChange: c}
+
+		original, err := os.ReadFile(c.path)
+		switch {
+		case err == nil:
+			p.original = original
+			p.existed = true
+		case !os.IsNotExist(err):
+			cleanup()
+			return err
+		}
+
+		if !c.remove {
+			p.tmp, err = writeTemp(c.path, c.content)
+			if err != nil {
+				cleanup()
+				return err
+			}
 		}
+		staged = append(staged, p)
+	}
 
-		num, err := strconv.Atoi(match[1])
+	for i, p := range staged {
+		var err error
+		if p.remove {
+			err = os.Remove(p.path)
+		} else {
+			err = rename(p.tmp, p.path)
+		}
 		if err != nil {
-			continue
+			// Roll back the changes that were already applied
+			for _, done := range staged[:i] {
+				if done.existed {
+					_ = writeFileAtomic(done.path, done.original)
+				} else {
+					_ = os.Remove(done.path)
+				}
+			}
+			cleanup()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Create saves a new ADR under the next available number, which is assigned
+// to adr.Number. Numbering is protected by the store's lock; if another file
+// with the same number still appears (e.g. written by an older stamp), the
+// new ADR is moved to the next free number.
+func (s *Store) Create(adr *ADR) error {
+	return s.WithLock(func() error {
+		for attempt := 0; attempt < createAttempts; attempt++ {
+			num, err := s.NextNumber()
+			if err != nil {
+				return err
+			}
+
+			adr.Number = num
+			adr.Filename = s.Scheme.FormatFilename(adr.Number, adr.Title, adr.Date)
+			path := filepath.Join(s.Directory, adr.Filename)
+
+			if err := createFileExclusive(path, []byte(adr.FormatMarkdown(s.Scheme))); err != nil {
+				if os.IsExist(err) {
+					continue
+				}
+				return err
+			}
+
+			if s.numberTaken(num, adr.Filename) {
+				_ = os.Remove(path)
+				continue
+			}
+
+			return nil
 		}
 
-		if num > maxNum {
-			maxNum = num
+		return fmt.Errorf("could not reserve an ADR number after %d attempts", createAttempts)
+	})
+}
+
+// createAttempts is how often Create retries when a number is already taken
+const createAttempts = 5
+
+// numberTaken reports whether a file other than filename uses number
+func (s *Store) numberTaken(number int, filename string) bool {
+	entries, err := os.ReadDir(s.Directory)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == filename {
+			continue
+		}
+		if parts, ok := s.Scheme.ParseFilename(entry.Name()); ok && parts.Number == number {
+			return true
 		}
 	}
 
-	return maxNum + 1, nil
+	return false
 }
 
-func (s *Store) FindByNumber(number int) (*ADR, error) {
-	adrs, err := s.List()
+// writeTemp writes data to a temporary file in the same directory as path and
+// returns its name, so it can later be renamed over path atomically. The file
+// gets the mode of path, or 0644 if path doesn't exist yet.
+func writeTemp(path string, data []byte) (string, error) {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := os.Chmod(f.Name(), mode); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// writeFileAtomic replaces path with data via a temporary file and a rename
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := writeTemp(path, data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
+
+// createFileExclusive writes data to path, failing with an os.ErrExist error
+// if path already exists. The content is staged in a temporary file and
+// hard-linked into place so the file never appears half-written; filesystems
+// without hard links fall back to O_EXCL.
+func createFileExclusive(path string, data []byte) error {
+	tmp, err := writeTemp(path, data)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	err = os.Link(tmp, path)
+	if err == nil || os.IsExist(err) {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
 	}
+	return f.Close()
+}
 
-	for _, adr := range adrs {
-		if adr.Number == number {
-			return adr, nil
+func (s *Store) NextNumber() (int, error) {
+	names, err := s.names()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 1, nil
 		}
+		return 0, err
 	}
+	return s.Scheme.NextNumber(names, time.Now())
+}
+
+// Delete removes the ADR stored in filename
+func (s *Store) Delete(filename string) error {
+	return os.Remove(filepath.Join(s.Directory, filename))
+}
 
-	return nil, os.ErrNotExist
+// Watch polls the ADR directory and reports files that are created,
+// modified or removed
+func (s *Store) Watch(ctx context.Context) (<-chan Event, error) {
+	return (&FSRepository{FS: os.DirFS(s.Directory), Scheme: s.Scheme}).Watch(ctx)
+}
+
+func (s *Store) FindByNumber(number int) (*ADR, error) {
+	return FindByNumber(s, number)
 }
@@ -1,8 +1,11 @@
 package adr
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -25,7 +28,7 @@ func TestStoreList(t *testing.T) {
 	store := NewStore(tmpDir)
 
 	// Test empty directory
-	adrs, err := store.List()
+	adrs, _, err := store.List()
 	if err != nil {
 		t.Fatalf("List() error on empty dir: %v", err)
 	}
@@ -73,7 +76,7 @@ func TestStoreList(t *testing.T) {
 	}
 
 	// Test listing
-	adrs, err = store.List()
+	adrs, _, err = store.List()
 	if err != nil {
 		t.Fatalf("List() error: %v", err)
 	}
@@ -92,7 +95,7 @@ func TestStoreList(t *testing.T) {
 
 func TestStoreListNonExistentDirectory(t *testing.T) {
 	store := NewStore("/nonexistent/path/that/does/not/exist")
-	_, err := store.List()
+	_, _, err := store.List()
 	if err == nil {
 		t.Error("List() expected error for non-existent directory")
 	}
@@ -335,7 +338,7 @@ func TestStoreNextNumberIgnoresNonADRFiles(t *testing.T) {
 	nonADRFiles := []string{
 		"README.md",
 		"notes.txt",
-		"1-invalid-format.md", // Missing leading zeros
+		"notes-1.md", // Number not first
 	}
 	for _, f := range nonADRFiles {
 		path := filepath.Join(tmpDir, f)
@@ -416,7 +419,7 @@ func TestStoreFindByNumber(t *testing.T) {
 	}
 }
 
-func TestFilenameRegex(t *testing.T) {
+func TestDefaultSchemeMatches(t *testing.T) {
 	tests := []struct {
 		filename string
 		match    bool
@@ -424,9 +427,10 @@ func TestFilenameRegex(t *testing.T) {
 		{"0001-test.md", true},
 		{"0012-some-title.md", true},
 		{"1234-another-one.md", true},
+		{"12345-past-9999.md", true},
 		{"0001-a.md", true},
 		{"README.md", false},
-		{"1-no-padding.md", false},
+		{"1-no-padding.md", true}, // the width only applies to new files
 		{"0001-test.txt", false},
 		{"0001.md", false},
 		{"test-0001.md", false},
@@ -435,10 +439,323 @@ func TestFilenameRegex(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.filename, func(t *testing.T) {
-			got := filenameRegex.MatchString(tt.filename)
+			got := DefaultScheme.Matches(tt.filename)
 			if got != tt.match {
-				t.Errorf("filenameRegex.MatchString(%q) = %v, want %v", tt.filename, got, tt.match)
+				t.Errorf("DefaultScheme.Matches(%q) = %v, want %v", tt.filename, got, tt.match)
 			}
 		})
 	}
 }
+
+func TestStoreSaveAtomic(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	adr := NewADR(1, "Atomic Save")
+	if err := store.Save(adr); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	adr.Status = StatusAccepted
+	if err := store.Save(adr); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory contains %d entries, want 1 (no leftover temp files)", len(entries))
+	}
+
+	loaded, err := store.Load(adr.Filename)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if loaded.Status != StatusAccepted {
+		t.Errorf("Status = %q, want %q", loaded.Status, StatusAccepted)
+	}
+}
+
+func TestStoreSaveKeepsMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on Windows")
+	}
+
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	adr := NewADR(1, "Private Decision")
+	if err := store.Save(adr); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	path := filepath.Join(tmpDir, adr.Filename)
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatalf("Chmod() error: %v", err)
+	}
+
+	adr.Status = StatusAccepted
+	if err := store.Save(adr); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("mode = %v, want %v", mode, os.FileMode(0600))
+	}
+}
+
+func TestStoreCreate(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	first := NewADR(0, "First")
+	if err := store.Create(first); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if first.Number != 1 || first.Filename != "0001-first.md" {
+		t.Errorf("Create() assigned %d/%q, want 1/%q", first.Number, first.Filename, "0001-first.md")
+	}
+
+	second := NewADR(0, "Second")
+	if err := store.Create(second); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if second.Number != 2 {
+		t.Errorf("Create() assigned %d, want 2", second.Number)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, LockFileName)); !os.IsNotExist(err) {
+		t.Error("Create() left the lock file behind")
+	}
+}
+
+func TestStoreCreateConcurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	const n = 10
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			errs <- store.Create(NewADR(0, fmt.Sprintf("Concurrent %d", i)))
+		}(i)
+	}
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+	}
+
+	adrs, _, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(adrs) != n {
+		t.Fatalf("List() returned %d ADRs, want %d", len(adrs), n)
+	}
+	for i, a := range adrs {
+		if a.Number != i+1 {
+			t.Errorf("ADR %d has number %d, want %d", i, a.Number, i+1)
+		}
+	}
+}
+
+func TestStoreSaveAll(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	a := NewADR(1, "First")
+	b := NewADR(2, "Second")
+	if err := store.SaveAll(a, b); err != nil {
+		t.Fatalf("SaveAll() error: %v", err)
+	}
+
+	for _, filename := range []string{"0001-first.md", "0002-second.md"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, filename)); err != nil {
+			t.Errorf("SaveAll() did not create %s", filename)
+		}
+	}
+}
+
+func TestStoreSaveAllRollsBack(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	a := NewADR(1, "First")
+	if err := store.Save(a); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	original, _ := os.ReadFile(filepath.Join(tmpDir, a.Filename))
+
+	// Let the first rename through and fail the second one
+	renames := 0
+	oldRename := rename
+	rename = func(from, to string) error {
+		renames++
+		if renames == 2 {
+			return errors.New("disk full")
+		}
+		return oldRename(from, to)
+	}
+	defer func() { rename = oldRename }()
+
+	a.Status = StatusAccepted
+	b := NewADR(2, "Second")
+	if err := store.SaveAll(a, b); err == nil {
+		t.Fatal("SaveAll() expected error")
+	}
+
+	if renames != 2 {
+		t.Fatalf("SaveAll() renamed %d files, want 2", renames)
+	}
+	content, _ := os.ReadFile(filepath.Join(tmpDir, a.Filename))
+	if string(content) != string(original) {
+		t.Error("SaveAll() did not restore the first ADR after a failure")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "0002-second.md")); !os.IsNotExist(err) {
+		t.Error("SaveAll() left the second ADR behind after a failure")
+	}
+
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 1 {
+		t.Errorf("SaveAll() left %d files, want only %s", len(entries), a.Filename)
+	}
+}
+
+func TestStoreSaveAllRollsBackNewFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	oldRename := rename
+	rename = func(from, to string) error {
+		if filepath.Base(to) == "0002-second.md" {
+			return errors.New("disk full")
+		}
+		return oldRename(from, to)
+	}
+	defer func() { rename = oldRename }()
+
+	if err := store.SaveAll(NewADR(1, "First"), NewADR(2, "Second")); err == nil {
+		t.Fatal("SaveAll() expected error")
+	}
+
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 0 {
+		t.Errorf("SaveAll() left %d files after a failure, want none", len(entries))
+	}
+}
+
+func TestStoreWithLockTimeout(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	oldTimeout := lockTimeout
+	lockTimeout = 50 * time.Millisecond
+	defer func() { lockTimeout = oldTimeout }()
+
+	lockPath := filepath.Join(tmpDir, LockFileName)
+	if err := os.WriteFile(lockPath, []byte("1"), 0644); err != nil {
+		t.Fatalf("Failed to create lock file: %v", err)
+	}
+
+	err := store.WithLock(func() error { return nil })
+	if !errors.Is(err, ErrLocked) {
+		t.Errorf("WithLock() error = %v, want ErrLocked", err)
+	}
+
+	// A stale lock is taken over
+	stale := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockPath, stale, stale); err != nil {
+		t.Fatalf("Chtimes() error: %v", err)
+	}
+
+	called := false
+	if err := store.WithLock(func() error { called = true; return nil }); err != nil {
+		t.Fatalf("WithLock() error with stale lock: %v", err)
+	}
+	if !called {
+		t.Error("WithLock() did not run the function")
+	}
+}
+
+func TestStoreLockOwnership(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+	lockPath := filepath.Join(tmpDir, LockFileName)
+
+	// A lock taken over while fn runs belongs to someone else and is kept
+	err := store.WithLock(func() error {
+		return os.WriteFile(lockPath, []byte("other"), 0644)
+	})
+	if err != nil {
+		t.Fatalf("WithLock() error: %v", err)
+	}
+	if data, err := os.ReadFile(lockPath); err != nil || string(data) != "other" {
+		t.Errorf("lock = %q, %v after release, want the other owner's lock kept", data, err)
+	}
+	_ = os.Remove(lockPath)
+
+	// A lock found stale by two processes is only taken away by the first one
+	if err := os.WriteFile(lockPath, []byte("1"), 0644); err != nil {
+		t.Fatalf("Failed to create lock file: %v", err)
+	}
+	stale := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockPath, stale, stale); err != nil {
+		t.Fatalf("Chtimes() error: %v", err)
+	}
+	breakStaleLock(lockPath, "first")
+	if err := os.WriteFile(lockPath, []byte("first"), 0644); err != nil {
+		t.Fatalf("Failed to create lock file: %v", err)
+	}
+	breakStaleLock(lockPath, "second")
+	if data, err := os.ReadFile(lockPath); err != nil || string(data) != "first" {
+		t.Errorf("lock = %q, %v, want the fresh lock put back", data, err)
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory contains %d entries, want only the lock", len(entries))
+	}
+}
+
+func TestStoreListDiagnostics(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	if err := store.Save(NewADR(1, "Valid")); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	broken := "# 7 Broken title\n\nDate: 2024-01-15\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "0007-broken.md"), []byte(broken), 0644); err != nil {
+		t.Fatalf("Failed to write broken ADR: %v", err)
+	}
+
+	adrs, diagnostics, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(adrs) != 1 {
+		t.Errorf("List() returned %d ADRs, want 1", len(adrs))
+	}
+	if len(diagnostics) != 1 {
+		t.Fatalf("List() returned %d diagnostics, want 1", len(diagnostics))
+	}
+	if diagnostics[0].File != "0007-broken.md" || diagnostics[0].Line != 1 {
+		t.Errorf("diagnostic = %v, want 0007-broken.md:1", diagnostics[0])
+	}
+
+	// Looking up the broken ADR explains why it was skipped
+	_, err = store.FindByNumber(7)
+	var d *Diagnostic
+	if !errors.As(err, &d) {
+		t.Errorf("FindByNumber(7) error = %v, want *Diagnostic", err)
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/config"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate a shell completion script",
+	Long: `Prints a script that makes your shell complete stamp's commands and flags,
+as well as ADR numbers (with their titles), statuses and relations.
+
+Bash (requires bash-completion):
+  source <(stamp completion bash)
+  # or, for every session:
+  stamp completion bash > /etc/bash_completion.d/stamp
+
+Zsh:
+  stamp completion zsh > "${fpath[1]}/_stamp"
+
+Fish:
+  stamp completion fish > ~/.config/fish/completions/stamp.fish
+
+PowerShell:
+  stamp completion powershell | Out-String | Invoke-Expression`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+		return fmt.Errorf("unsupported shell: %s (expected bash, zsh, fish or powershell)", args[0])
+	},
+}
+
+// completeArgs completes each positional argument with the function at its
+// position, and nothing past the last one
+func completeArgs(funcs ...cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) >= len(funcs) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return funcs[len(args)](cmd, args, toComplete)
+	}
+}
+
+// completeADRNumbers completes the numbers of the ADRs in the active
+// collection, described by their titles
+func completeADRNumbers(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	repo, err := openRepository()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	adrs, _, err := repo.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]cobra.Completion, 0, len(adrs))
+	for _, a := range adrs {
+		num := strconv.Itoa(a.Number)
+		if strings.HasPrefix(num, toComplete) {
+			completions = append(completions, cobra.CompletionWithDesc(num, a.Title))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeStatuses completes the valid statuses, in lowercase
+func completeStatuses(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	completions := make([]cobra.Completion, 0, len(adr.ValidStatuses))
+	for _, s := range adr.ValidStatuses {
+		completions = append(completions, strings.ToLower(string(s)))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeRelations completes the relations of 'stamp link'
+func completeRelations(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	completions := make([]cobra.Completion, 0, len(validRelations))
+	for relation, display := range validRelations {
+		completions = append(completions, cobra.CompletionWithDesc(relation, display))
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCollections completes the names of the configured collections
+func completeCollections(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	path, err := configPath()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return cfg.CollectionNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
@@ -0,0 +1,471 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stef16robbe/stamp/internal/config"
+	"github.com/stef16robbe/stamp/internal/ui"
+)
+
+// configUser makes the config subcommands work on the user configuration file
+var configUser bool
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change the configuration",
+	Long: `Reads and writes settings without editing YAML by hand.
+
+Keys are the names used in .stamp.yaml; settings of a collection are
+addressed as collections.<name>.<key>. By default the project file is changed;
+with --user, the user configuration file shared by every project.
+
+Examples:
+  stamp config list
+  stamp config get prefix
+  stamp config set width 3
+  stamp config set collections.payments.prefix PAY
+  stamp config set --user editor "code --wait"
+  stamp config unset graph_format
+  stamp config validate
+  stamp config migrate`,
+}
+
+// configPath returns the project configuration file: --config, $STAMP_CONFIG
+// or the .stamp.yaml found from the current directory
+func configPath() (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+	if path := os.Getenv(config.EnvConfig); path != "" {
+		return path, nil
+	}
+	return config.FindConfigFile()
+}
+
+// targetConfigPath returns the file changed by set, unset and edit
+func targetConfigPath() (string, error) {
+	if configUser {
+		return config.UserConfigPath()
+	}
+	return configPath()
+}
+
+// validateConfig validates the content of a project or user configuration
+// file. With checkDirs, ADR directories must exist next to a project file.
+func validateConfig(data []byte, path string, checkDirs bool) ([]*config.Diagnostic, error) {
+	if isUserConfig(path) {
+		return config.ValidateUser(data, path)
+	}
+	data, _, err := config.Upgrade(data, path)
+	if err != nil {
+		return nil, err
+	}
+	root := ""
+	if checkDirs {
+		root = filepath.Dir(path)
+	}
+	return config.Validate(data, path, root)
+}
+
+// isUserConfig reports whether path is the user configuration file
+func isUserConfig(path string) bool {
+	userPath, err := config.UserConfigPath()
+	return err == nil && userPath == path
+}
+
+// effectiveConfig returns the configuration whose values get, list and
+// version use: the layered project configuration, or only the user settings
+// with --user or outside a project
+func effectiveConfig() (*config.Config, error) {
+	if configUser {
+		s, err := config.UserSettings()
+		if err != nil {
+			return nil, err
+		}
+		return &config.Config{Settings: s}, nil
+	}
+
+	cfg, err := loadConfig()
+	if err == nil {
+		return cfg, nil
+	}
+	if _, pathErr := configPath(); pathErr == nil {
+		return nil, err
+	}
+	s, err := config.LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+	return &config.Config{Settings: s}, nil
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := config.LookupKey(args[0]); err != nil {
+			return err
+		}
+
+		cfg, err := effectiveConfig()
+		if err != nil {
+			return err
+		}
+
+		v, err := cfg.Get(args[0])
+		if err != nil {
+			return err
+		}
+		if v.Value == "" || (configUser && !v.IsSet) {
+			return fmt.Errorf("%s is not set", args[0])
+		}
+		fmt.Fprintln(ui.Stdout, v.Value)
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the effective settings",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := effectiveConfig()
+		if err != nil {
+			return err
+		}
+
+		for _, v := range cfg.Values() {
+			switch {
+			case v.IsSet:
+				fmt.Fprintf(ui.Stdout, "%s = %s\n", ui.Bold(v.Key), v.Value)
+			case !configUser:
+				fmt.Fprintf(ui.Stdout, "%s = %s %s\n", ui.Bold(v.Key), v.Value, ui.Muted("(default)"))
+			}
+		}
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := config.LookupKey(args[0])
+		if err != nil {
+			return err
+		}
+		if configUser && !key.User {
+			return fmt.Errorf("%s is a project setting and can't be set in the user configuration", args[0])
+		}
+		value, err := key.Parse(args[1])
+		if err != nil {
+			return err
+		}
+
+		path, err := targetConfigPath()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil && !(configUser && errors.Is(err, os.ErrNotExist)) {
+			return err
+		}
+
+		updated, err := config.SetValue(data, args[0], value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		before := loadProjectConfig(path)
+		if err := writeConfig(path, updated); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(ui.Stdout, ui.Success(fmt.Sprintf("Set %s = %s in %s", ui.Bold(args[0]), args[1], ui.Muted(path))))
+		warnSchemeChange(before, loadProjectConfig(path))
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting, restoring its default",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := config.LookupKey(args[0]); err != nil {
+			return err
+		}
+
+		path, err := targetConfigPath()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if configUser && errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("%s is not set in %s", args[0], path)
+			}
+			return err
+		}
+
+		updated, found, err := config.UnsetValue(data, args[0])
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if !found {
+			return fmt.Errorf("%s is not set in %s", args[0], path)
+		}
+		before := loadProjectConfig(path)
+		if err := writeConfig(path, updated); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(ui.Stdout, ui.Success("Unset "+ui.Bold(args[0])+" in "+ui.Muted(path)))
+		warnSchemeChange(before, loadProjectConfig(path))
+		return nil
+	},
+}
+
+// loadProjectConfig loads the project configuration file at path, or returns
+// nil for the user configuration and files that can't be loaded
+func loadProjectConfig(path string) *config.Config {
+	if isUserConfig(path) {
+		return nil
+	}
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
+// warnSchemeChange warns about existing ADR files affected by a change of
+// the ID settings: files no longer read as ADRs, such as those named or
+// titled after the old templates, and files no longer named like new ones
+func warnSchemeChange(before, after *config.Config) {
+	if before == nil || after == nil {
+		return
+	}
+	oldCols, err := before.ResolveCollections()
+	if err != nil {
+		return
+	}
+	newCols, err := after.ResolveCollections()
+	if err != nil {
+		return
+	}
+
+	for _, col := range newCols {
+		i := slices.IndexFunc(oldCols, func(c *config.ResolvedCollection) bool {
+			return c.Name == col.Name && c.Directory == col.Directory
+		})
+		if i < 0 || oldCols[i].Scheme == col.Scheme {
+			continue
+		}
+		oldADRs, _, err := oldCols[i].Store().List()
+		if err != nil {
+			continue
+		}
+		newADRs, _, err := col.Store().List()
+		if err != nil {
+			continue
+		}
+
+		listed := make(map[string]bool, len(newADRs))
+		misnamed := 0
+		for _, a := range newADRs {
+			listed[a.Filename] = true
+			if oldCols[i].Scheme.Canonical(a.Filename) && !col.Scheme.Canonical(a.Filename) {
+				misnamed++
+			}
+		}
+		var lost []string
+		for _, a := range oldADRs {
+			if !listed[a.Filename] {
+				lost = append(lost, a.Filename)
+			}
+		}
+
+		where := col.Directory
+		if col.Name != "" {
+			where = "collection " + col.Name
+		}
+		if len(lost) > 0 {
+			fmt.Fprintln(ui.Stdout, ui.Warning(fmt.Sprintf("%d ADR(s) in %s no longer match the settings and will be skipped: %s",
+				len(lost), where, strings.Join(lost, ", "))))
+		}
+		if misnamed > 0 {
+			fmt.Fprintln(ui.Stdout, ui.Warning(fmt.Sprintf("%d ADR file(s) in %s keep names that don't follow the settings; 'stamp doctor' lists them",
+				misnamed, where)))
+		}
+	}
+}
+
+// writeConfig validates the new content of a configuration file and writes
+// it, so set and unset never leave an invalid file behind
+func writeConfig(path string, data []byte) error {
+	diagnostics, err := validateConfig(data, path, false)
+	if err != nil {
+		return err
+	}
+	if len(diagnostics) > 0 {
+		return fmt.Errorf("the change would make the configuration invalid:\n%w", errors.Join(diagnosticErrors(diagnostics)...))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the configuration file in your editor",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := targetConfigPath()
+		if err != nil {
+			return err
+		}
+
+		if s, err := config.LoadSettings(); err == nil {
+			settings = s
+		}
+		editorCmd, err := editorCommand(path)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := editorCmd.Run(); err != nil {
+			return fmt.Errorf("failed to open editor: %w", err)
+		}
+
+		return reportConfig(path)
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration for unknown keys and invalid values",
+	Long: `Checks the project configuration file and the user configuration file.
+Unknown keys, invalid values and ADR directories that don't exist are reported
+with their line and column.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var paths []string
+		if !configUser {
+			path, err := configPath()
+			if err != nil {
+				return err
+			}
+			paths = append(paths, path)
+		}
+		if path, err := config.UserConfigPath(); err == nil {
+			if _, err := os.Stat(path); err == nil {
+				paths = append(paths, path)
+			}
+		}
+
+		var failed bool
+		for _, path := range paths {
+			if err := reportConfig(path); err != nil {
+				failed = true
+			}
+		}
+		if failed {
+			return errors.New("invalid configuration")
+		}
+		return nil
+	},
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the project configuration to the current format",
+	Long: `Rewrites .stamp.yaml in the configuration format of this version of stamp,
+keeping comments, and records the format in its version key.
+
+A file written for a newer stamp is refused by older versions, which ask to
+upgrade stamp rather than misread it.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configUser {
+			return errors.New("the user configuration holds only personal settings and has no version to migrate")
+		}
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		m, err := config.Migrate(data, path)
+		if err != nil {
+			return err
+		}
+		if len(m.Changes) == 0 {
+			fmt.Fprintln(ui.Stdout, ui.Success(fmt.Sprintf("%s is already at version %d", ui.Muted(path), m.To)))
+			return nil
+		}
+		if err := writeConfig(path, m.Data); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(ui.Stdout, ui.Success(fmt.Sprintf("Migrated %s from version %d to %d", ui.Muted(path), m.From, m.To)))
+		for _, change := range m.Changes {
+			fmt.Fprintln(ui.Stdout, ui.Muted("  - "+change))
+		}
+		return nil
+	},
+}
+
+// reportConfig validates a configuration file, printing its problems
+func reportConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	diagnostics, err := validateConfig(data, path, true)
+	if err != nil {
+		fmt.Fprintln(ui.Stderr, ui.Warning(err.Error()))
+		return err
+	}
+
+	if len(diagnostics) == 0 {
+		fmt.Fprintln(ui.Stdout, ui.Success(ui.Muted(path)+" is valid"))
+		if _, version, err := config.Upgrade(data, path); err == nil && version < config.CurrentVersion && !isUserConfig(path) {
+			fmt.Fprintln(ui.Stdout, ui.Muted(fmt.Sprintf("  version %d is outdated; run 'stamp config migrate' to upgrade it to %d", version, config.CurrentVersion)))
+		}
+		return nil
+	}
+	for _, d := range diagnostics {
+		fmt.Fprintln(ui.Stderr, ui.Warning(d.Error()))
+	}
+	return fmt.Errorf("%s has %d problem(s)", path, len(diagnostics))
+}
+
+func diagnosticErrors(diagnostics []*config.Diagnostic) []error {
+	errs := make([]error, len(diagnostics))
+	for i, d := range diagnostics {
+		errs[i] = d
+	}
+	return errs
+}
+
+func init() {
+	configCmd.PersistentFlags().BoolVar(&configUser, "user", false, "Use the user configuration file instead of the project's")
+	configCmd.AddCommand(configGetCmd, configListCmd, configSetCmd, configUnsetCmd, configEditCmd, configValidateCmd, configMigrateCmd)
+	rootCmd.AddCommand(configCmd)
+}
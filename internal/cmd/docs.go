@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+	"github.com/stef16robbe/stamp/internal/ui"
+)
+
+// docsManOut and docsMarkdownOut are the directories the reference is
+// written to, set with --out
+var (
+	docsManOut      string
+	docsMarkdownOut string
+)
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate the CLI reference",
+	Long: `Generates documentation for every stamp command from its help text.
+
+Set SOURCE_DATE_EPOCH to date man pages reproducibly.`,
+}
+
+var docsManCmd = &cobra.Command{
+	Use:   "man",
+	Short: "Generate man pages",
+	Long: `Writes a man page in section 1 for every command, such as stamp.1 and
+stamp-show.1.
+
+Example:
+  stamp docs man --out /usr/local/share/man/man1`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date, err := docsDate()
+		if err != nil {
+			return err
+		}
+		header := &doc.GenManHeader{
+			Title:   "STAMP",
+			Section: "1",
+			Date:    &date,
+			Source:  "stamp " + Version,
+			Manual:  "Stamp Manual",
+		}
+		return generateDocs("man pages", docsManOut, func(dir string) error {
+			return doc.GenManTree(rootCmd, header, dir)
+		})
+	},
+}
+
+var docsMarkdownCmd = &cobra.Command{
+	Use:   "markdown",
+	Short: "Generate the Markdown CLI reference",
+	Long: `Writes a Markdown page for every command, such as stamp.md and stamp_show.md,
+linked to each other.
+
+Example:
+  stamp docs markdown --out docs/cli`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return generateDocs("Markdown reference", docsMarkdownOut, func(dir string) error {
+			return doc.GenMarkdownTree(rootCmd, dir)
+		})
+	},
+}
+
+// generateDocs creates the output directory and writes the reference into it
+func generateDocs(what, dir string, generate func(dir string) error) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	// The generated date would make every page change on each run
+	rootCmd.DisableAutoGenTag = true
+	if err := generate(dir); err != nil {
+		return fmt.Errorf("failed to generate %s: %w", what, err)
+	}
+
+	fmt.Fprintln(ui.Stdout, ui.Success("Wrote "+what+" to "+ui.Bold(dir)))
+	return nil
+}
+
+// docsDate returns the date printed in man pages: $SOURCE_DATE_EPOCH for
+// reproducible builds, or today
+func docsDate() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Now(), nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH: %s", epoch)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+func init() {
+	docsManCmd.Flags().StringVarP(&docsManOut, "out", "o", "man", "Directory to write the man pages to")
+	docsMarkdownCmd.Flags().StringVarP(&docsMarkdownOut, "out", "o", "docs/cli", "Directory to write the Markdown pages to")
+	docsCmd.AddCommand(docsManCmd, docsMarkdownCmd)
+	rootCmd.AddCommand(docsCmd)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/ui"
+)
+
+// doctorProblem is an issue found by 'stamp doctor', with a hint on how to fix it
+type doctorProblem struct {
+	Message string
+	Hint    string
+}
+
+// misnamedWarning reports ADR files whose number isn't padded to the
+// configured width, typically after the width changed. They are still read,
+// so this is a warning rather than a problem.
+func misnamedWarning(names []string) doctorProblem {
+	return doctorProblem{
+		Message: fmt.Sprintf("%d ADR file(s) are not named with the configured number width: %s", len(names), strings.Join(names, ", ")),
+		Hint:    "new ADRs are named like " + active.Scheme.FormatFilename(1, "title", time.Now()) + "; rename old ones with 'stamp renumber <file> --to <its number>' if you like",
+	}
+}
+
+// collisionProblem reports a number used by more than one ADR file
+func collisionProblem(dup *adr.DuplicateNumberError) doctorProblem {
+	return doctorProblem{
+		Message: fmt.Sprintf("ADR %s is used by %d files: %s", formatNumber(dup.Number), len(dup.Filenames), strings.Join(dup.Filenames, ", ")),
+		Hint:    fmt.Sprintf("run 'stamp renumber %s' to give it a new number", dup.Filenames[len(dup.Filenames)-1]),
+	}
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the ADR directory for problems",
+	Long: `Checks the ADR directory for problems, such as ADR files that cannot be
+parsed or several ADR files sharing the same number after merging branches.
+ADRs under review with fewer reviewers than review.quorum, which can't be
+accepted, are reported as warnings.
+
+Exits with a non-zero status when problems are found, so it can run in CI.`,
+	Annotations: map[string]string{revisionAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		col, err := resolveCollection(cfg)
+		if err != nil {
+			return err
+		}
+		repo, err := openCollection(cfg, col)
+		if err != nil {
+			return err
+		}
+		adrs, diagnostics, err := repo.List()
+		if err != nil {
+			return fmt.Errorf("failed to list ADRs: %w", err)
+		}
+
+		var problems []doctorProblem
+		for _, d := range diagnostics {
+			if d.Duplicate != nil {
+				problems = append(problems, collisionProblem(d.Duplicate))
+				continue
+			}
+			problems = append(problems, doctorProblem{
+				Message: d.Error(),
+				Hint:    "this file is skipped by every command until it is fixed",
+			})
+		}
+
+		var misnamed []string
+		for _, a := range adrs {
+			if !active.Scheme.Canonical(a.Filename) {
+				misnamed = append(misnamed, a.Filename)
+			}
+		}
+		var warnings []doctorProblem
+		if len(misnamed) > 0 {
+			warnings = append(warnings, misnamedWarning(misnamed))
+		}
+		for _, a := range adrs {
+			if a.Status != adr.StatusProposed {
+				continue
+			}
+			if msg := quorumWarning("ADR "+formatNumber(a.Number), a, cfg.Review.Quorum); msg != "" {
+				warnings = append(warnings, doctorProblem{Message: msg})
+			}
+		}
+		for _, w := range warnings {
+			fmt.Fprintln(ui.Stdout, ui.Warning(w.Message))
+			if w.Hint != "" {
+				fmt.Fprintln(ui.Stdout, "  "+ui.Muted(w.Hint))
+			}
+		}
+
+		if len(problems) == 0 {
+			fmt.Fprintln(ui.Stdout, ui.Success(fmt.Sprintf("No problems found in %d ADRs", len(adrs))))
+			return nil
+		}
+
+		for _, p := range problems {
+			fmt.Fprintln(ui.Stdout, ui.Error(p.Message))
+			if p.Hint != "" {
+				fmt.Fprintln(ui.Stdout, "  "+ui.Muted(p.Hint))
+			}
+		}
+
+		cmd.SilenceUsage = true
+		return fmt.Errorf("found %d problem(s)", len(problems))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -1,59 +1,40 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
-	"strconv"
 
 	"github.com/spf13/cobra"
-	"github.com/stef16robbe/stamp/internal/adr"
-	"github.com/stef16robbe/stamp/internal/config"
 )
 
 var editCmd = &cobra.Command{
-	Use:   "edit <number>",
-	Short: "Edit an ADR in your editor",
-	Long:  `Opens an Architecture Decision Record in $EDITOR (or $VISUAL).`,
-	Args:  cobra.ExactArgs(1),
+	Use:               "edit <number>",
+	Short:             "Edit an ADR in your editor",
+	Long:              `Opens an Architecture Decision Record in the configured editor, $VISUAL or $EDITOR.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeADRNumbers),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		num, err := strconv.Atoi(args[0])
+		cfg, err := loadConfig()
 		if err != nil {
-			return fmt.Errorf("invalid ADR number: %s", args[0])
-		}
-
-		editor := os.Getenv("VISUAL")
-		if editor == "" {
-			editor = os.Getenv("EDITOR")
-		}
-		if editor == "" {
-			return fmt.Errorf("no editor configured (set $EDITOR or $VISUAL)")
+			return err
 		}
 
-		cfg, err := config.Load()
+		col, num, err := resolveADRRef(cfg, args[0])
 		if err != nil {
 			return err
 		}
 
-		dir, err := cfg.ADRDirectory()
+		store := col.Store()
+
+		a, err := findADR(store, num)
 		if err != nil {
 			return err
 		}
 
-		store := adr.NewStore(dir)
-
-		a, err := store.FindByNumber(num)
+		editorCmd, err := editorCommand(filepath.Join(col.Directory, a.Filename))
 		if err != nil {
-			return fmt.Errorf("ADR %04d not found", num)
+			return err
 		}
 
-		filePath := filepath.Join(dir, a.Filename)
-		editorCmd := exec.Command(editor, filePath)
-		editorCmd.Stdin = os.Stdin
-		editorCmd.Stdout = os.Stdout
-		editorCmd.Stderr = os.Stderr
-
 		return editorCmd.Run()
 	},
 }
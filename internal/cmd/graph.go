@@ -1,17 +1,33 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/stef16robbe/stamp/internal/adr"
 	"github.com/stef16robbe/stamp/internal/config"
+	"github.com/stef16robbe/stamp/internal/diagram"
+	"github.com/stef16robbe/stamp/internal/ui"
 )
 
-var graphFormat string
+var (
+	graphFormat       string
+	graphRoot         string
+	graphDepth        int
+	graphDirection    string
+	graphStatuses     []string
+	graphTags         []string
+	graphHideIsolated bool
+	graphOut          string
+)
 
 // Link represents a relationship between two ADRs
 type Link struct {
@@ -20,15 +36,18 @@ type Link struct {
 	Relation string
 }
 
-// linkRegex matches lines like "Supersedes [ADR-0001](0001-title.md)"
-var linkRegex = regexp.MustCompile(`^(Supersedes|Superseded by|Amends|Amended by|Clarifies|Clarified by)\s+\[ADR-(\d+)\]`)
+// linkRegex matches lines like "Supersedes [ADR-0001](0001-title.md)",
+// whatever the ID prefix
+var linkRegex = regexp.MustCompile(`^(Supersedes|Superseded by|Amends|Amended by|Clarifies|Clarified by)\s+\[[A-Za-z][A-Za-z0-9]*-(\d+)\](?:\(([^)]*)\))?`)
 
-// parseLinks extracts links from an ADR's StatusExtra field
+// parseLinks extracts links from an ADR's StatusExtra field. Links to ADRs of
+// other collections, whose target is a path rather than a file in the same
+// directory, are skipped.
 func parseLinks(a *adr.ADR) []Link {
 	var links []Link
 	for _, line := range a.StatusExtra {
 		match := linkRegex.FindStringSubmatch(line)
-		if match == nil {
+		if match == nil || strings.Contains(match[3], "/") {
 			continue
 		}
 		relation := match[1]
@@ -45,6 +64,128 @@ func parseLinks(a *adr.ADR) []Link {
 	return links
 }
 
+// collectEdges returns the forward relations between ADRs. Reverse relations
+// ("superseded by", "amended by", ...) are skipped to avoid duplicate edges.
+func collectEdges(adrs []*adr.ADR) []Link {
+	var edges []Link
+	seen := make(map[string]bool)
+	for _, a := range adrs {
+		for _, link := range parseLinks(a) {
+			if strings.HasSuffix(link.Relation, " by") {
+				continue
+			}
+
+			edgeKey := fmt.Sprintf("%d-%d", link.Source, link.Target)
+			if seen[edgeKey] {
+				continue
+			}
+			seen[edgeKey] = true
+
+			edges = append(edges, link)
+		}
+	}
+	return edges
+}
+
+// graphFilter describes which part of the ADR graph should be rendered
+type graphFilter struct {
+	Root         int // 0 renders the full graph
+	Depth        int // 0 means unlimited
+	Direction    string
+	Statuses     []adr.Status
+	Tags         []string // ADRs need at least one of these tags
+	HideIsolated bool
+}
+
+// filterGraph reduces the graph to the nodes and edges selected by f
+func filterGraph(adrs []*adr.ADR, edges []Link, f graphFilter) ([]*adr.ADR, []Link, error) {
+	include := make(map[int]bool)
+	for _, a := range adrs {
+		if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, a.Status) {
+			continue
+		}
+		if len(f.Tags) > 0 && !slices.ContainsFunc(f.Tags, a.HasTag) {
+			continue
+		}
+		include[a.Number] = true
+	}
+
+	edges = keepEdges(edges, include)
+
+	if f.Root != 0 {
+		if !include[f.Root] {
+			return nil, nil, fmt.Errorf("ADR %s not found (or excluded by filters)", formatNumber(f.Root))
+		}
+		include = neighborhood(f.Root, edges, f.Depth, f.Direction)
+		edges = keepEdges(edges, include)
+	}
+
+	if f.HideIsolated {
+		connected := make(map[int]bool)
+		for _, e := range edges {
+			connected[e.Source] = true
+			connected[e.Target] = true
+		}
+		for num := range include {
+			if !connected[num] && num != f.Root {
+				delete(include, num)
+			}
+		}
+	}
+
+	var nodes []*adr.ADR
+	for _, a := range adrs {
+		if include[a.Number] {
+			nodes = append(nodes, a)
+		}
+	}
+
+	return nodes, edges, nil
+}
+
+// keepEdges returns the edges whose endpoints are both included
+func keepEdges(edges []Link, include map[int]bool) []Link {
+	var kept []Link
+	for _, e := range edges {
+		if include[e.Source] && include[e.Target] {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// neighborhood walks the graph breadth-first from root and returns every ADR
+// reachable within depth hops. "out" follows edges from source to target,
+// "in" follows them backwards and "both" ignores their direction.
+func neighborhood(root int, edges []Link, depth int, direction string) map[int]bool {
+	visited := map[int]bool{root: true}
+	frontier := []int{root}
+
+	for hop := 0; len(frontier) > 0 && (depth == 0 || hop < depth); hop++ {
+		var next []int
+		for _, num := range frontier {
+			for _, e := range edges {
+				var neighbor int
+				switch {
+				case direction != "in" && e.Source == num:
+					neighbor = e.Target
+				case direction != "out" && e.Target == num:
+					neighbor = e.Source
+				default:
+					continue
+				}
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return visited
+}
+
 // relationToArrow maps relations to Mermaid arrow styles
 var relationToArrow = map[string]string{
 	"Supersedes":    "-->|supersedes|",
@@ -65,64 +206,60 @@ var statusToStyle = map[adr.Status]string{
 	adr.StatusRejected:   ":::rejected",
 }
 
-func generateMermaid(adrs []*adr.ADR) string {
+// colorFor returns the node colors of a status in the current theme, shared
+// by every graph format
+func colorFor(status adr.Status) ui.StatusColors {
+	return ui.CurrentTheme().Status(status)
+}
+
+// nodeLabel returns the display label of an ADR node numbered with scheme,
+// truncating long titles
+func nodeLabel(scheme adr.Scheme, a *adr.ADR) string {
+	title := a.Title
+	if len(title) > 30 {
+		title = title[:27] + "..."
+	}
+	return fmt.Sprintf("%s: %s", scheme.FormatNumber(a.Number), title)
+}
+
+// isDashed reports whether a relation is drawn with a dashed line
+func isDashed(relation string) bool {
+	return relation == "Amends" || relation == "Clarifies"
+}
+
+func generateMermaid(scheme adr.Scheme, adrs []*adr.ADR, edges []Link) string {
 	var sb strings.Builder
 
 	sb.WriteString("graph TD\n")
 
 	// Define style classes
-	sb.WriteString("    classDef draft fill:#6b7280,stroke:#374151\n")
-	sb.WriteString("    classDef proposed fill:#3b82f6,stroke:#1d4ed8\n")
-	sb.WriteString("    classDef accepted fill:#22c55e,stroke:#15803d\n")
-	sb.WriteString("    classDef deprecated fill:#f59e0b,stroke:#d97706\n")
-	sb.WriteString("    classDef superseded fill:#a855f7,stroke:#7e22ce\n")
-	sb.WriteString("    classDef rejected fill:#ef4444,stroke:#b91c1c\n")
+	for _, status := range adr.ValidStatuses {
+		color := colorFor(status)
+		fmt.Fprintf(&sb, "    classDef %s fill:%s,stroke:%s,color:%s\n", strings.ToLower(string(status)), color.Fill, color.Stroke, color.Text)
+	}
 	sb.WriteString("\n")
 
 	// Create nodes for each ADR
 	for _, a := range adrs {
-		nodeID := fmt.Sprintf("ADR%d", a.Number)
-		// Truncate long titles
-		title := a.Title
-		if len(title) > 30 {
-			title = title[:27] + "..."
-		}
-		label := fmt.Sprintf("%04d: %s", a.Number, title)
 		style := statusToStyle[a.Status]
-		fmt.Fprintf(&sb, "    %s[\"%s\"]%s\n", nodeID, label, style)
+		fmt.Fprintf(&sb, "    %s[\"%s\"]%s\n", nodeID(scheme, a.Number), nodeLabel(scheme, a), style)
 	}
 
 	sb.WriteString("\n")
 
-	// Create edges - only use forward relations to avoid duplicates
-	seen := make(map[string]bool)
-	for _, a := range adrs {
-		links := parseLinks(a)
-		for _, link := range links {
-			// Only output forward relations (supersedes, amends, clarifies)
-			// Skip reverse relations to avoid duplicate edges
-			if strings.HasSuffix(link.Relation, " by") {
-				continue
-			}
-
-			edgeKey := fmt.Sprintf("%d-%d", link.Source, link.Target)
-			if seen[edgeKey] {
-				continue
-			}
-			seen[edgeKey] = true
-
-			arrow := relationToArrow[link.Relation]
-			if arrow == "" {
-				arrow = "-->"
-			}
-			fmt.Fprintf(&sb, "    ADR%d %s ADR%d\n", link.Source, arrow, link.Target)
+	// Create edges
+	for _, link := range edges {
+		arrow := relationToArrow[link.Relation]
+		if arrow == "" {
+			arrow = "-->"
 		}
+		fmt.Fprintf(&sb, "    %s %s %s\n", nodeID(scheme, link.Source), arrow, nodeID(scheme, link.Target))
 	}
 
 	return sb.String()
 }
 
-func generateDot(adrs []*adr.ADR) string {
+func generateDot(scheme adr.Scheme, adrs []*adr.ADR, edges []Link) string {
 	var sb strings.Builder
 
 	sb.WriteString("digraph ADRs {\n")
@@ -130,111 +267,397 @@ func generateDot(adrs []*adr.ADR) string {
 	sb.WriteString("    node [shape=box, style=rounded];\n")
 	sb.WriteString("\n")
 
-	// Status colors for DOT
-	statusColor := map[adr.Status]string{
-		adr.StatusDraft:      "#6b7280",
-		adr.StatusProposed:   "#3b82f6",
-		adr.StatusAccepted:   "#22c55e",
-		adr.StatusDeprecated: "#f59e0b",
-		adr.StatusSuperseded: "#a855f7",
-		adr.StatusRejected:   "#ef4444",
+	// Create nodes
+	for _, a := range adrs {
+		color := colorFor(a.Status)
+		fmt.Fprintf(&sb, "    %s [label=\"%s\", fillcolor=\"%s\", style=\"filled,rounded\", fontcolor=\"%s\"];\n",
+			nodeID(scheme, a.Number), nodeLabel(scheme, a), color.Fill, color.Text)
+	}
+
+	sb.WriteString("\n")
+
+	// Create edges
+	for _, link := range edges {
+		style := "solid"
+		if isDashed(link.Relation) {
+			style = "dashed"
+		}
+		fmt.Fprintf(&sb, "    %s -> %s [label=\"%s\", style=%s];\n",
+			nodeID(scheme, link.Source), nodeID(scheme, link.Target), strings.ToLower(link.Relation), style)
 	}
 
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func generatePlantUML(scheme adr.Scheme, adrs []*adr.ADR, edges []Link) string {
+	var sb strings.Builder
+
+	sb.WriteString("@startuml\n")
+	sb.WriteString("skinparam rectangle {\n")
+	sb.WriteString("    RoundCorner 10\n")
+	sb.WriteString("}\n")
+	sb.WriteString("\n")
+
 	// Create nodes
 	for _, a := range adrs {
-		title := a.Title
-		if len(title) > 30 {
-			title = title[:27] + "..."
-		}
-		label := fmt.Sprintf("%04d: %s", a.Number, title)
-		color := statusColor[a.Status]
-		if color == "" {
-			color = "#6b7280"
+		color := colorFor(a.Status)
+		label := strings.ReplaceAll(nodeLabel(scheme, a), `"`, "'")
+		fmt.Fprintf(&sb, "rectangle \"%s\" as %s %s;line:%s;text:%s\n",
+			label, nodeID(scheme, a.Number), color.Fill, strings.TrimPrefix(color.Stroke, "#"), strings.TrimPrefix(color.Text, "#"))
+	}
+
+	sb.WriteString("\n")
+
+	// Create edges
+	for _, link := range edges {
+		arrow := "-->"
+		if isDashed(link.Relation) {
+			arrow = "..>"
 		}
-		fmt.Fprintf(&sb, "    ADR%d [label=\"%s\", fillcolor=\"%s\", style=\"filled,rounded\", fontcolor=\"white\"];\n",
-			a.Number, label, color)
+		fmt.Fprintf(&sb, "%s %s %s : %s\n", nodeID(scheme, link.Source), arrow, nodeID(scheme, link.Target), strings.ToLower(link.Relation))
+	}
+
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+func generateD2(scheme adr.Scheme, adrs []*adr.ADR, edges []Link) string {
+	var sb strings.Builder
+
+	sb.WriteString("direction: down\n")
+	sb.WriteString("\n")
+
+	// Create nodes
+	for _, a := range adrs {
+		color := colorFor(a.Status)
+		label := strings.ReplaceAll(nodeLabel(scheme, a), `"`, `\"`)
+		fmt.Fprintf(&sb, "%s: \"%s\" {\n", nodeID(scheme, a.Number), label)
+		fmt.Fprintf(&sb, "  style.fill: \"%s\"\n", color.Fill)
+		fmt.Fprintf(&sb, "  style.stroke: \"%s\"\n", color.Stroke)
+		fmt.Fprintf(&sb, "  style.font-color: \"%s\"\n", color.Text)
+		sb.WriteString("  style.border-radius: 8\n")
+		sb.WriteString("}\n")
 	}
 
 	sb.WriteString("\n")
 
 	// Create edges
-	seen := make(map[string]bool)
+	for _, link := range edges {
+		fmt.Fprintf(&sb, "%s -> %s: %s", nodeID(scheme, link.Source), nodeID(scheme, link.Target), strings.ToLower(link.Relation))
+		if isDashed(link.Relation) {
+			sb.WriteString(" {\n  style.stroke-dash: 3\n}")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// jsonGraph is the node/edge document emitted by --format json
+type jsonGraph struct {
+	Nodes []jsonNode `json:"nodes"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+type jsonNode struct {
+	ID       string `json:"id"`
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Date     string `json:"date"`
+	Filename string `json:"filename"`
+	Color    string `json:"color"`
+}
+
+type jsonEdge struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Relation string `json:"relation"`
+}
+
+func generateJSON(scheme adr.Scheme, adrs []*adr.ADR, edges []Link) (string, error) {
+	graph := jsonGraph{
+		Nodes: make([]jsonNode, 0, len(adrs)),
+		Edges: make([]jsonEdge, 0, len(edges)),
+	}
+
 	for _, a := range adrs {
-		links := parseLinks(a)
-		for _, link := range links {
-			if strings.HasSuffix(link.Relation, " by") {
-				continue
-			}
+		graph.Nodes = append(graph.Nodes, jsonNode{
+			ID:       nodeID(scheme, a.Number),
+			Number:   a.Number,
+			Title:    a.Title,
+			Status:   string(a.Status),
+			Date:     a.Date.Format("2006-01-02"),
+			Filename: a.Filename,
+			Color:    colorFor(a.Status).Fill,
+		})
+	}
 
-			edgeKey := fmt.Sprintf("%d-%d", link.Source, link.Target)
-			if seen[edgeKey] {
-				continue
-			}
-			seen[edgeKey] = true
+	for _, link := range edges {
+		graph.Edges = append(graph.Edges, jsonEdge{
+			Source:   nodeID(scheme, link.Source),
+			Target:   nodeID(scheme, link.Target),
+			Relation: strings.ToLower(link.Relation),
+		})
+	}
 
-			style := "solid"
-			if link.Relation == "Amends" || link.Relation == "Clarifies" {
-				style = "dashed"
-			}
-			fmt.Fprintf(&sb, "    ADR%d -> ADR%d [label=\"%s\", style=%s];\n",
-				link.Source, link.Target, strings.ToLower(link.Relation), style)
+	data, err := json.MarshalIndent(graph, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// xmlEscape escapes text for use in XML attributes and character data
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+func generateGraphML(scheme adr.Scheme, adrs []*adr.ADR, edges []Link) string {
+	var sb strings.Builder
+
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	sb.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:y="http://www.yworks.com/xml/graphml">` + "\n")
+	sb.WriteString(`  <key id="title" for="node" attr.name="title" attr.type="string"/>` + "\n")
+	sb.WriteString(`  <key id="status" for="node" attr.name="status" attr.type="string"/>` + "\n")
+	sb.WriteString(`  <key id="date" for="node" attr.name="date" attr.type="string"/>` + "\n")
+	sb.WriteString(`  <key id="color" for="node" attr.name="color" attr.type="string"/>` + "\n")
+	sb.WriteString(`  <key id="graphics" for="node" yfiles.type="nodegraphics"/>` + "\n")
+	sb.WriteString(`  <key id="relation" for="edge" attr.name="relation" attr.type="string"/>` + "\n")
+	sb.WriteString(`  <key id="edgegraphics" for="edge" yfiles.type="edgegraphics"/>` + "\n")
+	sb.WriteString(`  <graph id="ADRs" edgedefault="directed">` + "\n")
+
+	// Create nodes
+	for _, a := range adrs {
+		color := colorFor(a.Status)
+		fmt.Fprintf(&sb, "    <node id=\"%s\">\n", nodeID(scheme, a.Number))
+		fmt.Fprintf(&sb, "      <data key=\"title\">%s</data>\n", xmlEscape(a.Title))
+		fmt.Fprintf(&sb, "      <data key=\"status\">%s</data>\n", xmlEscape(string(a.Status)))
+		fmt.Fprintf(&sb, "      <data key=\"date\">%s</data>\n", a.Date.Format("2006-01-02"))
+		fmt.Fprintf(&sb, "      <data key=\"color\">%s</data>\n", color.Fill)
+		sb.WriteString("      <data key=\"graphics\">\n")
+		sb.WriteString("        <y:ShapeNode>\n")
+		sb.WriteString("          <y:Geometry width=\"220.0\" height=\"40.0\"/>\n")
+		fmt.Fprintf(&sb, "          <y:Fill color=\"%s\" transparent=\"false\"/>\n", color.Fill)
+		fmt.Fprintf(&sb, "          <y:BorderStyle color=\"%s\" type=\"line\" width=\"1.0\"/>\n", color.Stroke)
+		fmt.Fprintf(&sb, "          <y:NodeLabel textColor=\"%s\">%s</y:NodeLabel>\n", color.Text, xmlEscape(nodeLabel(scheme, a)))
+		sb.WriteString("          <y:Shape type=\"roundrectangle\"/>\n")
+		sb.WriteString("        </y:ShapeNode>\n")
+		sb.WriteString("      </data>\n")
+		sb.WriteString("    </node>\n")
+	}
+
+	// Create edges
+	for i, link := range edges {
+		lineType := "line"
+		if isDashed(link.Relation) {
+			lineType = "dashed"
 		}
+		relation := strings.ToLower(link.Relation)
+		fmt.Fprintf(&sb, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, nodeID(scheme, link.Source), nodeID(scheme, link.Target))
+		fmt.Fprintf(&sb, "      <data key=\"relation\">%s</data>\n", relation)
+		sb.WriteString("      <data key=\"edgegraphics\">\n")
+		sb.WriteString("        <y:PolyLineEdge>\n")
+		fmt.Fprintf(&sb, "          <y:LineStyle color=\"#000000\" type=\"%s\" width=\"1.0\"/>\n", lineType)
+		sb.WriteString("          <y:Arrows source=\"none\" target=\"standard\"/>\n")
+		fmt.Fprintf(&sb, "          <y:EdgeLabel>%s</y:EdgeLabel>\n", relation)
+		sb.WriteString("        </y:PolyLineEdge>\n")
+		sb.WriteString("      </data>\n")
+		sb.WriteString("    </edge>\n")
 	}
 
-	sb.WriteString("}\n")
+	sb.WriteString("  </graph>\n")
+	sb.WriteString("</graphml>\n")
 	return sb.String()
 }
 
+// buildDiagram positions the ADR graph for SVG and PNG rendering
+func buildDiagram(scheme adr.Scheme, adrs []*adr.ADR, edges []Link) *diagram.Diagram {
+	nodes := make([]diagram.NodeSpec, len(adrs))
+	for i, a := range adrs {
+		color := colorFor(a.Status)
+		nodes[i] = diagram.NodeSpec{
+			ID:     a.Number,
+			Label:  nodeLabel(scheme, a),
+			Fill:   color.Fill,
+			Text:   color.Text,
+			Stroke: color.Stroke,
+		}
+	}
+
+	specs := make([]diagram.EdgeSpec, len(edges))
+	for i, link := range edges {
+		specs[i] = diagram.EdgeSpec{
+			From:   link.Source,
+			To:     link.Target,
+			Label:  strings.ToLower(link.Relation),
+			Dashed: isDashed(link.Relation),
+		}
+	}
+
+	return diagram.Build(nodes, specs)
+}
+
+func generatePNG(scheme adr.Scheme, adrs []*adr.ADR, edges []Link) (string, error) {
+	var buf bytes.Buffer
+	if err := buildDiagram(scheme, adrs, edges).PNG(&buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
 var graphCmd = &cobra.Command{
 	Use:   "graph",
 	Short: "Generate a visual graph of ADR relationships",
-	Long: `Generate a graph showing ADR relationships in Mermaid or Graphviz DOT format.
-
-Mermaid graphs can be rendered directly in GitHub markdown or using the Mermaid CLI.
-DOT graphs can be rendered using Graphviz (e.g., dot -Tpng graph.dot -o graph.png).
+	Long: `Generate a graph showing ADR relationships.
+
+Supported formats:
+  mermaid   Rendered directly in GitHub markdown or using the Mermaid CLI
+  dot       Graphviz DOT (e.g., dot -Tpng graph.dot -o graph.png)
+  plantuml  PlantUML component diagram
+  d2        D2 diagram (e.g., d2 graph.d2 graph.svg)
+  json      Node/edge document for Cytoscape, D3 and similar tools
+  graphml   GraphML with yEd styling
+  ascii     Box-and-arrow diagram drawn directly in the terminal
+  svg       SVG image, laid out without Graphviz or Node
+  png       PNG image (requires --out when writing to a terminal)
 
 Examples:
   stamp graph                     # Output Mermaid format
   stamp graph --format mermaid    # Output Mermaid format (explicit)
   stamp graph --format dot        # Output Graphviz DOT format
-  stamp graph > docs/adr-graph.md # Save to file`,
+  stamp graph --format graphml    # Output GraphML for yEd
+  stamp graph --format ascii      # Draw the graph in the terminal
+  stamp graph --format svg --out docs/adr-graph.svg
+  stamp graph --format png --out docs/adr-graph.png
+  stamp graph > docs/adr-graph.md # Save to file
+
+Focused views:
+  stamp graph --root 12                      # ADR 0012 and its direct neighbors
+  stamp graph --root 12 --depth 0            # Everything connected to ADR 0012
+  stamp graph --root 12 --direction out      # Only what ADR 0012 supersedes/amends/clarifies
+  stamp graph --root PAY-3                   # ADR 3 of the PAY collection and its neighbors
+  stamp graph --status accepted,proposed     # Only ADRs with the given statuses
+  stamp graph --tag security,storage         # Only ADRs with any of the given tags
+  stamp graph --status accepted --hide-isolated`,
+	Annotations: map[string]string{revisionAnnotation: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load()
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
-
-		dir, err := cfg.ADRDirectory()
+		// A root in another collection graphs that collection
+		var col *config.ResolvedCollection
+		root := 0
+		if graphRoot != "" {
+			col, root, err = resolveADRRef(cfg, graphRoot)
+		} else {
+			col, err = resolveCollection(cfg)
+		}
 		if err != nil {
 			return err
 		}
-
-		store := adr.NewStore(dir)
-		adrs, err := store.List()
+		repo, err := openCollection(cfg, col)
+		if err != nil {
+			return err
+		}
+		adrs, diagnostics, err := repo.List()
 		if err != nil {
 			return fmt.Errorf("failed to list ADRs: %w", err)
 		}
+		warnSkipped(diagnostics)
 
 		if len(adrs) == 0 {
 			return fmt.Errorf("no ADRs found")
 		}
 
+		filter := graphFilter{
+			Root:         root,
+			Depth:        graphDepth,
+			Direction:    strings.ToLower(graphDirection),
+			HideIsolated: graphHideIsolated,
+		}
+		switch filter.Direction {
+		case "in", "out", "both":
+		default:
+			return fmt.Errorf("invalid direction: %s (valid: in, out, both)", graphDirection)
+		}
+		if graphDepth < 0 {
+			return fmt.Errorf("invalid depth: %d (must be 0 or greater)", graphDepth)
+		}
+		for _, s := range graphStatuses {
+			status, err := adr.ParseStatus(s)
+			if err != nil {
+				return err
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+		filter.Tags = adr.ParseTags(strings.Join(graphTags, ","))
+
+		nodes, edges, err := filterGraph(adrs, collectEdges(adrs), filter)
+		if err != nil {
+			return err
+		}
+
+		if len(nodes) == 0 {
+			return fmt.Errorf("no ADRs match the given filters")
+		}
+
+		if !cmd.Flags().Changed("format") {
+			graphFormat = settings.GraphFormat
+		}
+
+		scheme := col.Scheme
 		var output string
 		switch graphFormat {
 		case "mermaid":
-			output = generateMermaid(adrs)
+			output = generateMermaid(scheme, nodes, edges)
 		case "dot":
-			output = generateDot(adrs)
+			output = generateDot(scheme, nodes, edges)
+		case "plantuml":
+			output = generatePlantUML(scheme, nodes, edges)
+		case "d2":
+			output = generateD2(scheme, nodes, edges)
+		case "json":
+			output, err = generateJSON(scheme, nodes, edges)
+			if err != nil {
+				return fmt.Errorf("failed to encode graph: %w", err)
+			}
+		case "graphml":
+			output = generateGraphML(scheme, nodes, edges)
+		case "ascii":
+			output = generateASCII(scheme, nodes, edges)
+		case "svg":
+			output = buildDiagram(scheme, nodes, edges).SVG()
+		case "png":
+			if graphOut == "" && ui.IsTerminal(os.Stdout) {
+				return fmt.Errorf("refusing to write PNG to a terminal (use --out)")
+			}
+			output, err = generatePNG(scheme, nodes, edges)
+			if err != nil {
+				return fmt.Errorf("failed to render PNG: %w", err)
+			}
 		default:
-			return fmt.Errorf("invalid format: %s (valid: mermaid, dot)", graphFormat)
+			return fmt.Errorf("invalid format: %s (valid: %s)", graphFormat, strings.Join(config.GraphFormats, ", "))
 		}
 
-		fmt.Print(output)
-		return nil
+		return writeOutput(output, graphOut, graphFormat == "png")
 	},
 }
 
 func init() {
-	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "mermaid", "Output format: mermaid or dot")
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "mermaid", "Output format: mermaid, dot, plantuml, d2, json, graphml, ascii, svg or png (overrides the graph_format setting)")
+	graphCmd.Flags().StringVarP(&graphOut, "out", "o", "", "Write the graph to a file instead of stdout")
+	graphCmd.Flags().StringVarP(&graphRoot, "root", "r", "", "Only render the neighborhood of this ADR (a number, PREFIX-N or collection:N)")
+	graphCmd.Flags().IntVarP(&graphDepth, "depth", "d", 1, "Number of hops from --root to include (0 for unlimited)")
+	graphCmd.Flags().StringVar(&graphDirection, "direction", "both", "Edges to follow from --root: in, out or both")
+	graphCmd.Flags().StringSliceVarP(&graphStatuses, "status", "s", nil, "Only include ADRs with these statuses (comma-separated)")
+	graphCmd.Flags().StringSliceVarP(&graphTags, "tag", "t", nil, "Only include ADRs with any of these tags (comma-separated)")
+	graphCmd.Flags().BoolVar(&graphHideIsolated, "hide-isolated", false, "Hide ADRs without any relationships")
+	_ = graphCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(config.GraphFormats, cobra.ShellCompDirectiveNoFileComp))
+	_ = graphCmd.RegisterFlagCompletionFunc("root", completeADRNumbers)
 	rootCmd.AddCommand(graphCmd)
 }
@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/layout"
+	"github.com/stef16robbe/stamp/internal/ui"
+)
+
+// Line directions stored in a canvas cell
+const (
+	lineUp = 1 << iota
+	lineDown
+	lineLeft
+	lineRight
+)
+
+const (
+	boxHeight = 3
+	boxGap    = 3
+)
+
+// canvasCell is a single character cell of the terminal graph
+type canvasCell struct {
+	text  rune
+	style int // index into canvas.styles, only used for text
+	lines int
+	solid bool
+}
+
+// canvas is a grid of cells holding box text and connecting lines
+type canvas struct {
+	cells  [][]canvasCell
+	styles []lipgloss.Style
+}
+
+func newCanvas(width, height int) *canvas {
+	c := &canvas{styles: []lipgloss.Style{lipgloss.NewStyle()}}
+	c.cells = make([][]canvasCell, height)
+	for y := range c.cells {
+		c.cells[y] = make([]canvasCell, width)
+	}
+	return c
+}
+
+func (c *canvas) addStyle(style lipgloss.Style) int {
+	c.styles = append(c.styles, style)
+	return len(c.styles) - 1
+}
+
+func (c *canvas) text(x, y int, s string, style int) {
+	for _, r := range s {
+		if y >= 0 && y < len(c.cells) && x >= 0 && x < len(c.cells[y]) {
+			c.cells[y][x].text = r
+			c.cells[y][x].style = style
+		}
+		x++
+	}
+}
+
+func (c *canvas) connect(x, y, dir int, solid bool) {
+	cell := &c.cells[y][x]
+	cell.lines |= dir
+	cell.solid = cell.solid || solid
+}
+
+// vline draws a vertical line from (x, y1) to (x, y2)
+func (c *canvas) vline(x, y1, y2 int, solid bool) {
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	for y := y1; y <= y2; y++ {
+		if y > y1 {
+			c.connect(x, y, lineUp, solid)
+		}
+		if y < y2 {
+			c.connect(x, y, lineDown, solid)
+		}
+	}
+}
+
+// hline draws a horizontal line from (x1, y) to (x2, y)
+func (c *canvas) hline(y, x1, x2 int, solid bool) {
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	for x := x1; x <= x2; x++ {
+		if x > x1 {
+			c.connect(x, y, lineLeft, solid)
+		}
+		if x < x2 {
+			c.connect(x, y, lineRight, solid)
+		}
+	}
+}
+
+// lineRunes maps a combination of line directions to a box-drawing character
+var lineRunes = map[int]rune{
+	lineUp:                                   '│',
+	lineDown:                                 '│',
+	lineUp | lineDown:                        '│',
+	lineLeft:                                 '─',
+	lineRight:                                '─',
+	lineLeft | lineRight:                     '─',
+	lineDown | lineRight:                     '╭',
+	lineDown | lineLeft:                      '╮',
+	lineUp | lineRight:                       '╰',
+	lineUp | lineLeft:                        '╯',
+	lineUp | lineDown | lineRight:            '├',
+	lineUp | lineDown | lineLeft:             '┤',
+	lineLeft | lineRight | lineDown:          '┬',
+	lineLeft | lineRight | lineUp:            '┴',
+	lineUp | lineDown | lineLeft | lineRight: '┼',
+}
+
+func (c *canvas) rune(cell canvasCell) rune {
+	if cell.text != 0 {
+		return cell.text
+	}
+	if cell.lines == 0 {
+		return ' '
+	}
+	if !cell.solid {
+		switch cell.lines {
+		case lineUp | lineDown:
+			return ui.Glyph('┆')
+		case lineLeft | lineRight:
+			return ui.Glyph('┄')
+		}
+	}
+	return ui.Glyph(lineRunes[cell.lines])
+}
+
+func (c *canvas) String() string {
+	lineStyle := lipgloss.NewStyle().Foreground(ui.MutedColor)
+
+	var sb strings.Builder
+	for _, row := range c.cells {
+		var line strings.Builder
+		var run strings.Builder
+		runStyle := -1
+		flush := func() {
+			if run.Len() == 0 {
+				return
+			}
+			switch {
+			case runStyle < 0:
+				line.WriteString(lineStyle.Render(run.String()))
+			case runStyle == 0:
+				line.WriteString(run.String())
+			default:
+				line.WriteString(c.styles[runStyle].Render(run.String()))
+			}
+			run.Reset()
+		}
+
+		// Trim trailing empty cells
+		end := len(row)
+		for end > 0 && row[end-1].text == 0 && row[end-1].lines == 0 {
+			end--
+		}
+
+		for _, cell := range row[:end] {
+			style := cell.style
+			if cell.text == 0 {
+				style = 0
+				if cell.lines != 0 {
+					style = -1
+				}
+			}
+			if style != runStyle {
+				flush()
+				runStyle = style
+			}
+			run.WriteRune(c.rune(cell))
+		}
+		flush()
+
+		sb.WriteString(line.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// generateASCII renders the ADR graph as a box-and-arrow diagram for the terminal
+func generateASCII(scheme adr.Scheme, adrs []*adr.ADR, edges []Link) string {
+	byNumber := make(map[int]*adr.ADR, len(adrs))
+	for _, a := range adrs {
+		byNumber[a.Number] = a
+	}
+
+	// Only lay out ADRs that have relationships; the rest are listed below
+	connected := make(map[int]bool)
+	layoutEdges := make([]layout.Edge, 0, len(edges))
+	relations := make(map[layout.Edge]string, len(edges))
+	for _, e := range edges {
+		connected[e.Source] = true
+		connected[e.Target] = true
+		le := layout.Edge{From: e.Source, To: e.Target}
+		layoutEdges = append(layoutEdges, le)
+		relations[le] = e.Relation
+	}
+
+	var nodes []int
+	var isolated []*adr.ADR
+	for _, a := range adrs {
+		if connected[a.Number] {
+			nodes = append(nodes, a.Number)
+		} else {
+			isolated = append(isolated, a)
+		}
+	}
+
+	var sb strings.Builder
+
+	if len(nodes) > 0 {
+		sb.WriteString(renderLayered(scheme, layout.Layered(nodes, layoutEdges), byNumber, relations))
+	}
+
+	if len(isolated) > 0 {
+		if len(nodes) > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(ui.Bold("Unlinked") + "\n")
+		}
+		for _, a := range isolated {
+			fmt.Fprintf(&sb, "  %s %s\n", ui.RenderStatus(a.Status), nodeLabel(scheme, a))
+		}
+	}
+
+	// Legend
+	sb.WriteString("\n")
+	seen := make(map[adr.Status]bool)
+	var badges []string
+	for _, status := range adr.ValidStatuses {
+		for _, a := range adrs {
+			if a.Status == status && !seen[status] {
+				seen[status] = true
+				badges = append(badges, ui.RenderStatus(status))
+			}
+		}
+	}
+	sb.WriteString(strings.Join(badges, " "))
+	if len(edges) > 0 {
+		sb.WriteString(ui.Muted(ui.Glyphs("   ── supersedes   ┄┄ amends/clarifies")))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+func renderLayered(scheme adr.Scheme, g *layout.Graph, byNumber map[int]*adr.ADR, relations map[layout.Edge]string) string {
+	width := func(id int) int {
+		if layout.IsDummy(id) {
+			return 1
+		}
+		return len([]rune(nodeLabel(scheme, byNumber[id]))) + 4
+	}
+
+	// Horizontal placement: each layer is packed left to right and centered
+	layerWidths := make([]int, len(g.Layers))
+	canvasWidth := 0
+	for r, layer := range g.Layers {
+		for i, id := range layer {
+			if i > 0 {
+				layerWidths[r] += boxGap
+			}
+			layerWidths[r] += width(id)
+		}
+		canvasWidth = max(canvasWidth, layerWidths[r])
+	}
+
+	left := make(map[int]int)
+	center := make(map[int]int)
+	for r, layer := range g.Layers {
+		x := (canvasWidth - layerWidths[r]) / 2
+		for _, id := range layer {
+			left[id] = x
+			center[id] = x + width(id)/2
+			x += width(id) + boxGap
+		}
+	}
+
+	// Vertical placement: each gap between layers gets one track per bent segment
+	type segment struct {
+		from, to int
+		solid    bool
+		track    int
+	}
+	gaps := make([][]*segment, len(g.Layers))
+	for _, p := range g.Paths {
+		relation := relations[p.Edge]
+		for i := 1; i < len(p.Nodes); i++ {
+			from, to := p.Nodes[i-1], p.Nodes[i]
+			gaps[g.Rank(from)] = append(gaps[g.Rank(from)], &segment{
+				from:  from,
+				to:    to,
+				solid: !isDashed(relation),
+				track: -1,
+			})
+		}
+	}
+
+	top := make([]int, len(g.Layers))
+	y := 0
+	for r := range g.Layers {
+		top[r] = y
+		tracks := 0
+		for _, s := range gaps[r] {
+			if center[s.from] != center[s.to] {
+				s.track = tracks
+				tracks++
+			}
+		}
+		y += boxHeight + tracks + 2
+	}
+
+	c := newCanvas(canvasWidth, y-2)
+
+	// Lines first, so box borders can show where edges attach
+	for r := range g.Layers {
+		bottom := top[r] + boxHeight - 1
+		for _, s := range gaps[r] {
+			x1, x2 := center[s.from], center[s.to]
+			targetTop := top[r+1]
+			if s.track < 0 {
+				c.vline(x1, bottom, targetTop, s.solid)
+				continue
+			}
+			trackY := bottom + 2 + s.track
+			c.vline(x1, bottom, trackY, s.solid)
+			c.hline(trackY, x1, x2, s.solid)
+			c.vline(x2, trackY, targetTop, s.solid)
+		}
+		for _, id := range g.Layers[r] {
+			if layout.IsDummy(id) {
+				c.vline(center[id], top[r], bottom, true)
+			}
+		}
+	}
+
+	// Arrowheads
+	arrowStyle := c.addStyle(lipgloss.NewStyle().Foreground(ui.HighlightColor))
+	for _, p := range g.Paths {
+		if p.Reversed {
+			first := p.Nodes[0]
+			c.text(center[first], top[g.Rank(first)]+boxHeight, ui.Glyphs("▲"), arrowStyle)
+			continue
+		}
+		last := p.Nodes[len(p.Nodes)-1]
+		c.text(center[last], top[g.Rank(last)]-1, ui.Glyphs("▼"), arrowStyle)
+	}
+
+	// Boxes
+	numberStyle := c.addStyle(lipgloss.NewStyle().Bold(true))
+	for r, layer := range g.Layers {
+		for _, id := range layer {
+			if layout.IsDummy(id) {
+				continue
+			}
+			a := byNumber[id]
+			border := c.addStyle(lipgloss.NewStyle().Foreground(ui.StatusStyles[a.Status].GetBackground()))
+			x, w := left[id], width(id)
+			y := top[r]
+
+			for i := 0; i < w; i++ {
+				topRune, bottomRune := '─', '─'
+				if y > 0 && c.cells[y-1][x+i].lines != 0 {
+					topRune = '┴'
+				}
+				if c.cells[y+2][x+i].lines&lineDown != 0 {
+					bottomRune = '┬'
+				}
+				switch i {
+				case 0:
+					topRune, bottomRune = '╭', '╰'
+				case w - 1:
+					topRune, bottomRune = '╮', '╯'
+				}
+				c.text(x+i, y, string(ui.Glyph(topRune)), border)
+				c.text(x+i, y+2, string(ui.Glyph(bottomRune)), border)
+			}
+			c.text(x, y+1, ui.Glyphs("│"), border)
+			c.text(x+w-1, y+1, ui.Glyphs("│"), border)
+
+			label := nodeLabel(scheme, a)
+			number, title, _ := strings.Cut(label, ":")
+			c.text(x+2, y+1, number, numberStyle)
+			c.text(x+2+len(number), y+1, ":"+title, 0)
+		}
+	}
+
+	return c.String()
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/ui"
+)
+
+// chain is 1 -> 2 -> 3 -> 4, with 5 amending 2
+var chain = []Link{
+	{Source: 1, Target: 2, Relation: "Supersedes"},
+	{Source: 2, Target: 3, Relation: "Supersedes"},
+	{Source: 3, Target: 4, Relation: "Supersedes"},
+	{Source: 5, Target: 2, Relation: "Amends"},
+}
+
+// cycle is 1 -> 2 -> 3 -> 1
+var cycle = []Link{
+	{Source: 1, Target: 2, Relation: "Clarifies"},
+	{Source: 2, Target: 3, Relation: "Clarifies"},
+	{Source: 3, Target: 1, Relation: "Clarifies"},
+}
+
+func TestNeighborhood(t *testing.T) {
+	tests := []struct {
+		name      string
+		root      int
+		edges     []Link
+		depth     int
+		direction string
+		want      []int
+	}{
+		{"one hop both ways", 2, chain, 1, "both", []int{1, 2, 3, 5}},
+		{"one hop out", 2, chain, 1, "out", []int{2, 3}},
+		{"one hop in", 2, chain, 1, "in", []int{1, 2, 5}},
+		{"two hops out", 1, chain, 2, "out", []int{1, 2, 3}},
+		{"unlimited out", 1, chain, 0, "out", []int{1, 2, 3, 4}},
+		{"unlimited in", 4, chain, 0, "in", []int{1, 2, 3, 4, 5}},
+		{"unlimited both", 4, chain, 0, "both", []int{1, 2, 3, 4, 5}},
+		{"leaf has nothing out", 4, chain, 0, "out", []int{4}},
+		{"root without edges", 9, chain, 0, "both", []int{9}},
+		{"cycle unlimited", 1, cycle, 0, "out", []int{1, 2, 3}},
+		{"cycle one hop in", 1, cycle, 1, "in", []int{1, 3}},
+		{"cycle both", 2, cycle, 1, "both", []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Sorted(maps.Keys(neighborhood(tt.root, tt.edges, tt.depth, tt.direction)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("neighborhood() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterGraph(t *testing.T) {
+	adrs := []*adr.ADR{
+		{Number: 1, Title: "One", Status: adr.StatusSuperseded, Tags: []string{"storage"}},
+		{Number: 2, Title: "Two", Status: adr.StatusAccepted, Tags: []string{"storage", "security"}},
+		{Number: 3, Title: "Three", Status: adr.StatusAccepted},
+		{Number: 4, Title: "Four", Status: adr.StatusProposed, Tags: []string{"security"}},
+		{Number: 5, Title: "Five", Status: adr.StatusAccepted},
+		{Number: 6, Title: "Six", Status: adr.StatusAccepted, Tags: []string{"api"}},
+	}
+
+	tests := []struct {
+		name      string
+		filter    graphFilter
+		wantNodes []int
+		wantEdges int
+		wantErr   bool
+	}{
+		{"no filter", graphFilter{Direction: "both"}, []int{1, 2, 3, 4, 5, 6}, 4, false},
+		{"status", graphFilter{Direction: "both", Statuses: []adr.Status{adr.StatusAccepted}}, []int{2, 3, 5, 6}, 2, false},
+		{"tag", graphFilter{Direction: "both", Tags: []string{"security"}}, []int{2, 4}, 0, false},
+		{"any of several tags", graphFilter{Direction: "both", Tags: []string{"security", "api"}}, []int{2, 4, 6}, 0, false},
+		{"tag case insensitive", graphFilter{Direction: "both", Tags: []string{"Storage"}}, []int{1, 2}, 1, false},
+		{"hide isolated", graphFilter{Direction: "both", HideIsolated: true}, []int{1, 2, 3, 4, 5}, 4, false},
+		{"status and hide isolated", graphFilter{Direction: "both", Statuses: []adr.Status{adr.StatusAccepted}, HideIsolated: true}, []int{2, 3, 5}, 2, false},
+		{"root", graphFilter{Root: 3, Depth: 1, Direction: "both"}, []int{2, 3, 4}, 2, false},
+		{"root keeps itself when isolated", graphFilter{Root: 6, Direction: "both", HideIsolated: true}, []int{6}, 0, false},
+		{"root excluded by filter", graphFilter{Root: 1, Direction: "both", Statuses: []adr.Status{adr.StatusAccepted}}, nil, 0, true},
+		{"filters apply before traversal", graphFilter{Root: 2, Depth: 0, Direction: "both", Statuses: []adr.Status{adr.StatusAccepted}}, []int{2, 3, 5}, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, edges, err := filterGraph(adrs, chain, tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("filterGraph() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []int
+			for _, a := range nodes {
+				got = append(got, a.Number)
+			}
+			if !slices.Equal(got, tt.wantNodes) {
+				t.Errorf("nodes = %v, want %v", got, tt.wantNodes)
+			}
+			if len(edges) != tt.wantEdges {
+				t.Errorf("edges = %v, want %d", edges, tt.wantEdges)
+			}
+		})
+	}
+}
+
+func TestGenerateMermaidUsesScheme(t *testing.T) {
+	scheme := adr.Scheme{Width: 5, Prefix: "PAY"}
+	adrs := []*adr.ADR{adr.NewADR(1, "Use Kafka"), adr.NewADR(2, "Use Pulsar")}
+	edges := []Link{{Source: 2, Target: 1, Relation: "Supersedes"}}
+
+	got := generateMermaid(scheme, adrs, edges)
+	for _, want := range []string{`PAY1["00001: Use Kafka"]`, "PAY2 -->|supersedes| PAY1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generateMermaid() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestDiagramsWithoutColorAreASCII(t *testing.T) {
+	if err := ui.SetColor("never"); err != nil {
+		t.Fatal(err)
+	}
+
+	supersedes := []string{"Supersedes [ADR-0001](0001-one.md)"}
+	amends := []string{"Amends [ADR-0002](0002-two.md)"}
+	adrs := []*adr.ADR{
+		{Number: 1, Title: "One", Date: day("2026-01-10"), Status: adr.StatusSuperseded, Filename: "0001-one.md"},
+		{Number: 2, Title: "Two", Date: day("2026-02-10"), Status: adr.StatusAccepted, Filename: "0002-two.md", StatusExtra: supersedes},
+		{Number: 3, Title: "Three", Date: day("2026-03-10"), Status: adr.StatusAccepted, Filename: "0003-three.md", StatusExtra: amends},
+	}
+	changes := []statusChange{{Date: day("2026-02-10"), Filename: "0001-one.md", From: adr.StatusAccepted, To: adr.StatusSuperseded}}
+
+	outputs := map[string]string{
+		"graph":    generateASCII(adr.DefaultScheme, adrs, collectEdges(adrs)),
+		"timeline": renderTimeline(buildTimeline(adrs, changes, time.Time{}), "month"),
+	}
+	for name, out := range outputs {
+		for _, r := range out {
+			if r > unicode.MaxASCII {
+				t.Errorf("%s output contains %q without color:\n%s", name, r, out)
+				break
+			}
+		}
+	}
+}
@@ -66,9 +66,9 @@ See [adr.github.io](https://adr.github.io/) for more information on ADRs.`,
 			return fmt.Errorf("failed to create initial ADR: %w", err)
 		}
 
-		fmt.Println(ui.Success("Initialized ADR directory at " + ui.Bold(cfg.Directory)))
-		fmt.Println(ui.Success("Configuration saved to " + ui.Muted(config.ConfigFileName)))
-		fmt.Println(ui.Success("Created " + ui.Muted(initialADR.Filename)))
+		fmt.Fprintln(ui.Stdout, ui.Success("Initialized ADR directory at "+ui.Bold(cfg.Directory)))
+		fmt.Fprintln(ui.Stdout, ui.Success("Configuration saved to "+ui.Muted(config.ConfigFileName)))
+		fmt.Fprintln(ui.Stdout, ui.Success("Created "+ui.Muted(initialADR.Filename)))
 
 		return nil
 	},
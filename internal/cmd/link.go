@@ -2,8 +2,8 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
+	"time"
 
 	"charm.land/lipgloss/v2"
 	"github.com/spf13/cobra"
@@ -43,19 +43,14 @@ Valid relations: supersedes, superseded-by, amends, amended-by, clarifies, clari
 Example:
   stamp link 2 1 supersedes
   # ADR 0002 gets "Supersedes [ADR-0001](...)"
-  # ADR 0001 gets "Superseded by [ADR-0002](...)" and status → Superseded`,
-	Args: cobra.ExactArgs(3),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		sourceNum, err := strconv.Atoi(args[0])
-		if err != nil {
-			return fmt.Errorf("invalid source ADR number: %s", args[0])
-		}
-
-		targetNum, err := strconv.Atoi(args[1])
-		if err != nil {
-			return fmt.Errorf("invalid target ADR number: %s", args[1])
-		}
+  # ADR 0001 gets "Superseded by [ADR-0002](...)" and status → Superseded
 
+ADRs of other collections are referred to by their ID prefix or collection
+name, e.g. "PAY-3" or "payments:3":
+  stamp link 5 PAY-3 amends`,
+	Args:              cobra.ExactArgs(3),
+	ValidArgsFunction: completeArgs(completeADRNumbers, completeADRNumbers, completeRelations),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		relation := strings.ToLower(args[2])
 		relationDisplay, ok := validRelations[relation]
 		if !ok {
@@ -66,73 +61,104 @@ Example:
 			return fmt.Errorf("invalid relation: %s (valid: %s)", args[2], strings.Join(validList, ", "))
 		}
 
-		cfg, err := config.Load()
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
 
-		dir, err := cfg.ADRDirectory()
+		current, err := resolveCollection(cfg)
 		if err != nil {
 			return err
 		}
 
-		store := adr.NewStore(dir)
-
-		source, err := store.FindByNumber(sourceNum)
+		sourceCol, sourceNum, err := resolveRef(cfg, current, args[0])
 		if err != nil {
-			return fmt.Errorf("source ADR %04d not found", sourceNum)
+			return fmt.Errorf("source: %w", err)
 		}
 
-		target, err := store.FindByNumber(targetNum)
+		targetCol, targetNum, err := resolveRef(cfg, current, args[1])
 		if err != nil {
-			return fmt.Errorf("target ADR %04d not found", targetNum)
+			return fmt.Errorf("target: %w", err)
 		}
 
-		// Add link to source ADR
-		sourceLinkLine := fmt.Sprintf("%s [ADR-%04d](%s)", relationDisplay, target.Number, target.Filename)
-		source.StatusExtra = append(source.StatusExtra, sourceLinkLine)
-
-		// Add reciprocal link to target ADR
-		reciprocalRelation := reciprocal[relation]
-		reciprocalDisplay := validRelations[reciprocalRelation]
-		targetLinkLine := fmt.Sprintf("%s [ADR-%04d](%s)", reciprocalDisplay, source.Number, source.Filename)
-		target.StatusExtra = append(target.StatusExtra, targetLinkLine)
+		sourceStore := sourceCol.Store()
+		targetStore := targetCol.Store()
 
-		// Update status for supersedes relationships
 		var oldStatus adr.Status
+		var changedCol *config.ResolvedCollection
 		var changedNum int
-		switch relation {
-		case "supersedes":
-			oldStatus = target.Status
-			changedNum = targetNum
-			target.Status = adr.StatusSuperseded
-		case "superseded-by":
-			oldStatus = source.Status
-			changedNum = sourceNum
-			source.Status = adr.StatusSuperseded
-		}
+		err = adr.WithLocks([]*adr.Store{sourceStore, targetStore}, func() error {
+			source, err := findADR(sourceStore, sourceNum)
+			if err != nil {
+				return fmt.Errorf("source %w", err)
+			}
 
-		if err := store.Save(source); err != nil {
-			return fmt.Errorf("failed to save source ADR: %w", err)
-		}
+			target, err := findADR(targetStore, targetNum)
+			if err != nil {
+				return fmt.Errorf("target %w", err)
+			}
+
+			// Add link to source ADR
+			sourceLinkLine := fmt.Sprintf("%s [%s](%s)", relationDisplay, adrID(targetCol, target.Number), linkHref(sourceCol, targetCol, target.Filename))
+			source.StatusExtra = append(source.StatusExtra, sourceLinkLine)
+
+			// Add reciprocal link to target ADR
+			reciprocalRelation := reciprocal[relation]
+			reciprocalDisplay := validRelations[reciprocalRelation]
+			targetLinkLine := fmt.Sprintf("%s [%s](%s)", reciprocalDisplay, adrID(sourceCol, source.Number), linkHref(targetCol, sourceCol, source.Filename))
+			target.StatusExtra = append(target.StatusExtra, targetLinkLine)
+
+			// Update status for supersedes relationships
+			switch relation {
+			case "supersedes":
+				oldStatus = target.Status
+				changedCol, changedNum = targetCol, targetNum
+				target.SetStatus(adr.StatusSuperseded, time.Now(), settings.Author, "Superseded by "+adrID(sourceCol, sourceNum))
+			case "superseded-by":
+				oldStatus = source.Status
+				changedCol, changedNum = sourceCol, sourceNum
+				source.SetStatus(adr.StatusSuperseded, time.Now(), settings.Author, "Superseded by "+adrID(targetCol, targetNum))
+			}
 
-		if err := store.Save(target); err != nil {
-			return fmt.Errorf("failed to save target ADR: %w", err)
+			// Both files are updated together, or neither is
+			err = adr.SaveAcross(
+				adr.StoredADR{Store: sourceStore, ADR: source},
+				adr.StoredADR{Store: targetStore, ADR: target},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to save ADRs: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 
-		arrow := lipgloss.NewStyle().Foreground(ui.Magenta).Render(" → ")
-		adrStyle := lipgloss.NewStyle().Foreground(ui.Cyan).Bold(true)
-		fmt.Println(ui.Success("Linked " + adrStyle.Render(fmt.Sprintf("ADR-%04d", sourceNum)) + arrow + adrStyle.Render(fmt.Sprintf("ADR-%04d", targetNum)) + ui.Muted(" ("+relationDisplay+")")))
+		arrow := lipgloss.NewStyle().Foreground(ui.HighlightColor).Render(" " + ui.Arrow + " ")
+		adrStyle := lipgloss.NewStyle().Foreground(ui.AccentColor).Bold(true)
+		fmt.Fprintln(ui.Stdout, ui.Success("Linked "+adrStyle.Render(adrID(sourceCol, sourceNum))+arrow+adrStyle.Render(adrID(targetCol, targetNum))+ui.Muted(" ("+relationDisplay+")")))
 
 		// Show status change if applicable
 		if relation == "supersedes" || relation == "superseded-by" {
-			fmt.Println(ui.Success(fmt.Sprintf("Updated ADR %04d: ", changedNum)) + ui.RenderStatusTransition(oldStatus, adr.StatusSuperseded))
+			// Reported like 'stamp status', numbered with the changed ADR's scheme
+			fmt.Fprintln(ui.Stdout, ui.Success("Updated ADR "+changedCol.Scheme.FormatNumber(changedNum)+": ")+ui.RenderStatusTransition(oldStatus, adr.StatusSuperseded))
 		}
 
 		return nil
 	},
 }
 
+// adrID formats the ID used in links to an ADR of col, like "ADR-0001"
+func adrID(col *config.ResolvedCollection, num int) string {
+	return col.FormatID(num)
+}
+
+// linkHref returns the link target of filename in collection to, as written
+// in an ADR of collection from
+func linkHref(from, to *config.ResolvedCollection, filename string) string {
+	return adr.LinkTarget(from.Directory, to.Directory, filename)
+}
+
 func init() {
 	rootCmd.AddCommand(linkCmd)
 }
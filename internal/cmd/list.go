@@ -2,73 +2,104 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"charm.land/lipgloss/v2"
 	"charm.land/lipgloss/v2/table"
 	"github.com/spf13/cobra"
-	"github.com/stef16robbe/stamp/internal/adr"
-	"github.com/stef16robbe/stamp/internal/config"
 	"github.com/stef16robbe/stamp/internal/ui"
 )
 
+// listChangedSince keeps the ADRs whose status changed on or after a date,
+// set with --changed-since
+var listChangedSince string
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all ADRs",
-	Long:  `Lists all Architecture Decision Records with their status and date.`,
+	Long: `Lists all Architecture Decision Records with their status and date.
+
+With --changed-since, lists only the ADRs whose status changed on or after a
+date according to their status history, with the date of the latest change.
+
+Example:
+  stamp list --changed-since 2026-07-01`,
+	Annotations: map[string]string{revisionAnnotation: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load()
-		if err != nil {
-			return err
+		var since time.Time
+		if listChangedSince != "" {
+			var err error
+			if since, err = parseDate(listChangedSince); err != nil {
+				return err
+			}
 		}
 
-		dir, err := cfg.ADRDirectory()
+		repo, err := openRepository()
 		if err != nil {
 			return err
 		}
 
-		store := adr.NewStore(dir)
-
-		adrs, err := store.List()
+		adrs, diagnostics, err := repo.List()
 		if err != nil {
 			return fmt.Errorf("failed to list ADRs: %w", err)
 		}
+		warnSkipped(diagnostics)
 
 		if len(adrs) == 0 {
-			fmt.Println(ui.Warning("No ADRs found. Create one with 'stamp new <title>'"))
+			fmt.Fprintln(ui.Stdout, ui.Warning("No ADRs found. Create one with 'stamp new <title>'"))
 			return nil
 		}
 
-		rows := make([][]string, len(adrs))
-		for i, a := range adrs {
-			rows[i] = []string{
-				fmt.Sprintf("%04d", a.Number),
-				a.Title,
-				ui.RenderStatus(a.Status),
-				a.Date.Format("2006-01-02"),
-			}
+		dateHeader := "DATE"
+		if !since.IsZero() {
+			dateHeader = "CHANGED"
 		}
 
-		t := table.New().
-			Border(lipgloss.RoundedBorder()).
-			BorderStyle(lipgloss.NewStyle().Foreground(ui.Gray)).
-			Headers("NUM", "TITLE", "STATUS", "DATE").
-			Rows(rows...).
-			StyleFunc(func(row, col int) lipgloss.Style {
-				if row == table.HeaderRow {
-					return lipgloss.NewStyle().
-						Bold(true).
-						Foreground(ui.Cyan).
-						Padding(0, 1)
+		rows := make([][]string, 0, len(adrs))
+		for _, a := range adrs {
+			date := a.Date
+			if !since.IsZero() {
+				date = a.LastChanged()
+				if date.Before(since) {
+					continue
 				}
-				return lipgloss.NewStyle().Padding(0, 1)
+			}
+			rows = append(rows, []string{
+				formatNumber(a.Number),
+				a.Title,
+				ui.RenderStatus(a.Status),
+				date.Format("2006-01-02"),
 			})
+		}
+
+		if len(rows) == 0 {
+			fmt.Fprintln(ui.Stdout, ui.Warning("No status changes since "+since.Format("2006-01-02")))
+			return nil
+		}
 
-		fmt.Println(t)
+		fmt.Fprintln(ui.Stdout, listTable([]string{"NUM", "TITLE", "STATUS", dateHeader}, rows))
 
 		return nil
 	},
 }
 
+// listTable renders rows in the table style of 'stamp list', shared by every
+// command printing a table
+func listTable(headers []string, rows [][]string) *table.Table {
+	return table.New().
+		Border(ui.TableBorder).
+		BorderStyle(lipgloss.NewStyle().Foreground(ui.MutedColor)).
+		Headers(headers...).
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return ui.TableHeaderStyle
+			}
+			return ui.TableCellStyle
+		})
+}
+
 func init() {
+	listCmd.Flags().StringVar(&listChangedSince, "changed-since", "", "Only list ADRs whose status changed on or after this date (YYYY-MM-DD)")
 	rootCmd.AddCommand(listCmd)
 }
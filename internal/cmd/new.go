@@ -2,18 +2,20 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/stef16robbe/stamp/internal/adr"
-	"github.com/stef16robbe/stamp/internal/config"
 	"github.com/stef16robbe/stamp/internal/ui"
 )
 
-var openEditor bool
+var (
+	openEditor  bool
+	newAuthor   string
+	newReviewBy string
+	newTags     []string
+)
 
 var newCmd = &cobra.Command{
 	Use:   "new <title>",
@@ -23,46 +25,43 @@ var newCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		title := strings.Join(args, " ")
 
-		cfg, err := config.Load()
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
 
-		dir, err := cfg.ADRDirectory()
+		col, err := resolveCollection(cfg)
 		if err != nil {
 			return err
 		}
 
-		store := adr.NewStore(dir)
+		store := col.Store()
 
-		nextNum, err := store.NextNumber()
-		if err != nil {
-			return fmt.Errorf("failed to determine next ADR number: %w", err)
+		// Create assigns the next free number while holding the store's lock
+		newADR := adr.NewADR(0, title)
+		newADR.Author = settings.Author
+		if cmd.Flags().Changed("author") {
+			newADR.Author = newAuthor
+		}
+		newADR.Tags = adr.ParseTags(strings.Join(newTags, ","))
+		if newReviewBy != "" {
+			if newADR.ReviewBy, err = parseDate(newReviewBy); err != nil {
+				return err
+			}
 		}
 
-		newADR := adr.NewADR(nextNum, title)
-
-		if err := store.Save(newADR); err != nil {
+		if err := store.Create(newADR); err != nil {
 			return fmt.Errorf("failed to save ADR: %w", err)
 		}
 
-		fmt.Println(ui.Success("Created " + ui.Muted(newADR.Filename)))
+		fmt.Fprintln(ui.Stdout, ui.Success("Created "+ui.Muted(newADR.Filename)))
 
 		if openEditor {
-			editor := os.Getenv("VISUAL")
-			if editor == "" {
-				editor = os.Getenv("EDITOR")
-			}
-			if editor == "" {
-				return fmt.Errorf("no editor configured (set $EDITOR or $VISUAL)")
+			editorCmd, err := editorCommand(filepath.Join(col.Directory, newADR.Filename))
+			if err != nil {
+				return err
 			}
 
-			filePath := filepath.Join(dir, newADR.Filename)
-			editorCmd := exec.Command(editor, filePath)
-			editorCmd.Stdin = os.Stdin
-			editorCmd.Stdout = os.Stdout
-			editorCmd.Stderr = os.Stderr
-
 			if err := editorCmd.Run(); err != nil {
 				return fmt.Errorf("failed to open editor: %w", err)
 			}
@@ -73,6 +72,9 @@ var newCmd = &cobra.Command{
 }
 
 func init() {
-	newCmd.Flags().BoolVarP(&openEditor, "editor", "e", false, "Open the new ADR in $EDITOR")
+	newCmd.Flags().BoolVarP(&openEditor, "editor", "e", false, "Open the new ADR in the configured editor")
+	newCmd.Flags().StringVar(&newAuthor, "author", "", "Author recorded in the ADR (default: the author setting)")
+	newCmd.Flags().StringVar(&newReviewBy, "review-by", "", "Date to revisit the decision by (YYYY-MM-DD), reported by 'stamp stale'")
+	newCmd.Flags().StringSliceVarP(&newTags, "tag", "t", nil, "Tags recorded in the ADR (comma-separated)")
 	rootCmd.AddCommand(newCmd)
 }
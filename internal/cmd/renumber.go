@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/stef16robbe/stamp/internal/ui"
+)
+
+var renumberTo int
+
+var renumberCmd = &cobra.Command{
+	Use:   "renumber <file>",
+	Short: "Give an ADR a new number",
+	Long: `Moves an ADR to a new number: the file is renamed, its heading rewritten and
+every [ADR-NNNN](file) link pointing at it from other ADRs, in any collection,
+is updated.
+
+Use this to resolve number collisions after merging branches that each created
+an ADR with the same number. Without --to, the next free number is used.
+
+Examples:
+  stamp renumber 0042-use-kafka.md
+  stamp renumber docs/adr/0042-use-kafka.md --to 50`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if renumberTo < 0 {
+			return fmt.Errorf("invalid ADR number: %d", renumberTo)
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		col, err := resolveCollection(cfg)
+		if err != nil {
+			return err
+		}
+
+		store, err := linkedStore(cfg, col)
+		if err != nil {
+			return err
+		}
+
+		result, err := store.Renumber(filepath.Base(args[0]), renumberTo)
+		if err != nil {
+			return fmt.Errorf("failed to renumber ADR: %w", err)
+		}
+
+		fmt.Fprintln(ui.Stdout, ui.Success(fmt.Sprintf("Renumbered %s %s %s", ui.Muted(result.OldFilename), ui.Arrow, ui.Bold(result.NewFilename))))
+		for _, filename := range result.Updated {
+			fmt.Fprintln(ui.Stdout, ui.Success("Updated links in "+ui.Muted(filename)))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	renumberCmd.Flags().IntVar(&renumberTo, "to", 0, "New ADR number (default: next available)")
+	rootCmd.AddCommand(renumberCmd)
+}
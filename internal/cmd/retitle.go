@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stef16robbe/stamp/internal/ui"
+)
+
+var retitleCmd = &cobra.Command{
+	Use:   "retitle <number> <new title>",
+	Short: "Change the title of an ADR",
+	Long: `Changes the title of an ADR: its heading is rewritten, the file is renamed to
+match the new title and every [ADR-NNNN](file) link pointing at it from other
+ADRs, in any collection, is updated. The ADR keeps its number.
+
+Example:
+  stamp retitle 7 Use Pulsar for events`,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeArgs(completeADRNumbers),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		title := strings.Join(args[1:], " ")
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		col, num, err := resolveADRRef(cfg, args[0])
+		if err != nil {
+			return err
+		}
+
+		store, err := linkedStore(cfg, col)
+		if err != nil {
+			return err
+		}
+
+		result, err := store.Retitle(num, title)
+		if err != nil {
+			return fmt.Errorf("failed to retitle ADR: %w", err)
+		}
+
+		if result.NewFilename == result.OldFilename {
+			fmt.Fprintln(ui.Stdout, ui.Success("Retitled ADR "+formatNumber(num)+" in "+ui.Muted(result.NewFilename)))
+		} else {
+			fmt.Fprintln(ui.Stdout, ui.Success(fmt.Sprintf("Renamed %s %s %s", ui.Muted(result.OldFilename), ui.Arrow, ui.Bold(result.NewFilename))))
+		}
+		for _, filename := range result.Updated {
+			fmt.Fprintln(ui.Stdout, ui.Success("Updated links in "+ui.Muted(filename)))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(retitleCmd)
+}
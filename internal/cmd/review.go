@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/config"
+	"github.com/stef16robbe/stamp/internal/ui"
+)
+
+var (
+	reviewReviewers []string
+	reviewComment   string
+	reviewAs        string
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Ask reviewers to sign off proposed ADRs",
+	Long: `Tracks the approval of proposed ADRs. Reviewers and their verdicts are
+recorded in a "Reviews" table in the ADR, and the ADR is accepted as soon as
+enough of them approve: review.quorum in .stamp.yaml, or every requested
+reviewer when it is unset. While any reviewer rejects the ADR, it is not
+accepted, however many approvals it has.
+
+Reviewers sign off under the author setting, or the name given with --as.
+
+Examples:
+  stamp review request 12 --reviewers alice,bob,carol
+  stamp review approve 12 --comment "Agreed, if we monitor lag"
+  stamp review reject 12 --as bob --comment "Needs a cost estimate"
+  stamp review status`,
+}
+
+var reviewRequestCmd = &cobra.Command{
+	Use:   "request <number>",
+	Short: "Ask reviewers to sign off an ADR",
+	Long: `Adds reviewers to an ADR. A draft is proposed at the same time; ADRs that
+are already decided can't be reviewed.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeADRNumbers),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(reviewReviewers) == 0 {
+			return fmt.Errorf("no reviewers given (use --reviewers alice,bob)")
+		}
+
+		cfg, col, num, err := loadReviewConfig(args[0])
+		if err != nil {
+			return err
+		}
+		store := col.Store()
+
+		var a *adr.ADR
+		var added []string
+		var oldStatus adr.Status
+		err = store.WithLock(func() error {
+			var err error
+			a, err = findADR(store, num)
+			if err != nil {
+				return err
+			}
+			if err := checkReviewable(a); err != nil {
+				return err
+			}
+
+			oldStatus = a.Status
+			added = a.RequestReview(time.Now(), reviewReviewers...)
+			if len(added) == 0 {
+				return nil
+			}
+			a.SetStatus(adr.StatusProposed, time.Now(), settings.Author, "Review requested from "+strings.Join(added, ", "))
+
+			if err := store.Save(a); err != nil {
+				return fmt.Errorf("failed to save ADR: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(added) == 0 {
+			fmt.Fprintln(ui.Stdout, ui.Warning("Every reviewer was already asked to review ADR "+formatNumber(num)))
+			return nil
+		}
+		fmt.Fprintln(ui.Stdout, ui.Success("Requested review of ADR "+formatNumber(num)+" from "+ui.Bold(strings.Join(added, ", "))))
+		if oldStatus != adr.StatusProposed {
+			fmt.Fprintln(ui.Stdout, ui.Success("Updated ADR "+formatNumber(num)+": ")+ui.RenderStatusTransition(oldStatus, adr.StatusProposed))
+		}
+		if msg := quorumWarning("ADR "+formatNumber(num), a, cfg.Review.Quorum); msg != "" {
+			fmt.Fprintln(ui.Stdout, ui.Warning(msg))
+		}
+		return nil
+	},
+}
+
+var reviewApproveCmd = &cobra.Command{
+	Use:               "approve <number>",
+	Short:             "Approve a proposed ADR",
+	Long:              `Records your approval of an ADR, accepting it once the quorum is reached.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeADRNumbers),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return signOff(args[0], adr.VerdictApproved)
+	},
+}
+
+var reviewRejectCmd = &cobra.Command{
+	Use:   "reject <number>",
+	Short: "Reject a proposed ADR",
+	Long: `Records your rejection of an ADR. The ADR stays proposed and can't be
+accepted until you approve it, so it can be revised and approved later; use
+'stamp status <number> rejected' to reject it for good.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeADRNumbers),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return signOff(args[0], adr.VerdictRejected)
+	},
+}
+
+var reviewStatusCmd = &cobra.Command{
+	Use:   "status [number]",
+	Short: "Show the reviews of proposed ADRs",
+	Long: `Without a number, lists every proposed ADR under review with its approvals.
+With a number, shows the verdict of each of its reviewers.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeArgs(completeADRNumbers),
+	Annotations:       map[string]string{revisionAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref := ""
+		if len(args) == 1 {
+			ref = args[0]
+		}
+		cfg, col, num, err := loadReviewConfig(ref)
+		if err != nil {
+			return err
+		}
+		repo, err := openCollection(cfg, col)
+		if err != nil {
+			return err
+		}
+
+		if ref != "" {
+			a, err := findADR(repo, num)
+			if err != nil {
+				return err
+			}
+			printReviews(a, cfg.Review.Quorum)
+			return nil
+		}
+
+		adrs, diagnostics, err := repo.List()
+		if err != nil {
+			return fmt.Errorf("failed to list ADRs: %w", err)
+		}
+		warnSkipped(diagnostics)
+
+		var rows [][]string
+		for _, a := range adrs {
+			if a.Status != adr.StatusProposed || len(a.Reviews) == 0 {
+				continue
+			}
+			rows = append(rows, []string{
+				formatNumber(a.Number),
+				a.Title,
+				approvalCount(a, cfg.Review.Quorum),
+				strings.Join(a.Reviewers(adr.VerdictPending), ", "),
+				strings.Join(a.Reviewers(adr.VerdictRejected), ", "),
+			})
+		}
+		if len(rows) == 0 {
+			fmt.Fprintln(ui.Stdout, ui.Warning("No ADRs under review. Ask for one with 'stamp review request <number> --reviewers <names>'"))
+			return nil
+		}
+
+		fmt.Fprintln(ui.Stdout, listTable([]string{"NUM", "TITLE", "APPROVALS", "PENDING", "REJECTED"}, rows))
+		return nil
+	},
+}
+
+// loadReviewConfig loads the configuration and the active collection. With a
+// reference to an ADR, the ADR's collection is the active one and its number
+// is returned too.
+func loadReviewConfig(ref string) (*config.Config, *config.ResolvedCollection, int, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if ref != "" {
+		col, num, err := resolveADRRef(cfg, ref)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		return cfg, col, num, nil
+	}
+	col, err := resolveCollection(cfg)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return cfg, col, 0, nil
+}
+
+// checkReviewable rejects reviews of ADRs that were already decided
+func checkReviewable(a *adr.ADR) error {
+	if a.Status != adr.StatusDraft && a.Status != adr.StatusProposed {
+		return fmt.Errorf("ADR %s is %s; only draft and proposed ADRs can be reviewed", formatNumber(a.Number), strings.ToLower(string(a.Status)))
+	}
+	return nil
+}
+
+// signOff records the verdict of the current reviewer on the ADR referenced
+// by arg, accepting it when the approvals reach the quorum
+func signOff(arg string, verdict adr.Verdict) error {
+	cfg, col, num, err := loadReviewConfig(arg)
+	if err != nil {
+		return err
+	}
+	reviewer := settings.Author
+	if reviewAs != "" {
+		reviewer = reviewAs
+	}
+	if reviewer == "" {
+		return fmt.Errorf("no reviewer name: set the author setting (stamp config set --user author <name>) or use --as")
+	}
+
+	store := col.Store()
+
+	var a *adr.ADR
+	var accepted bool
+	err = store.WithLock(func() error {
+		var err error
+		a, err = findADR(store, num)
+		if err != nil {
+			return err
+		}
+		if a.Status != adr.StatusProposed {
+			return fmt.Errorf("ADR %s is %s, not proposed; ask for a review with 'stamp review request'", formatNumber(num), strings.ToLower(string(a.Status)))
+		}
+
+		if err := a.SignOff(reviewer, verdict, time.Now(), reviewComment); err != nil {
+			return fmt.Errorf("%w of ADR %s (reviewers: %s)", err, formatNumber(num), strings.Join(reviewerNames(a), ", "))
+		}
+		// As written when the review was requested
+		reviewer = a.FindReview(reviewer).Reviewer
+		if verdict == adr.VerdictApproved && a.Approved(cfg.Review.Quorum) {
+			accepted = a.SetStatus(adr.StatusAccepted, time.Now(), reviewer,
+				"Approved by "+strings.Join(a.Reviewers(adr.VerdictApproved), ", "))
+		}
+
+		if err := store.Save(a); err != nil {
+			return fmt.Errorf("failed to save ADR: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(ui.Stdout, ui.Success(ui.Bold(reviewer)+" "+strings.ToLower(string(verdict))+" ADR "+formatNumber(num)+
+		ui.Muted(" ("+approvalCount(a, cfg.Review.Quorum)+" approvals)")))
+	if accepted {
+		fmt.Fprintln(ui.Stdout, ui.Success("Updated ADR "+formatNumber(num)+": ")+ui.RenderStatusTransition(adr.StatusProposed, adr.StatusAccepted))
+	} else if rejected := a.Reviewers(adr.VerdictRejected); verdict == adr.VerdictApproved && len(rejected) > 0 {
+		fmt.Fprintln(ui.Stdout, ui.Muted("Not accepted while rejected by "+strings.Join(rejected, ", ")))
+	}
+	return nil
+}
+
+// quorumWarning returns a warning when the ADR named name has fewer
+// reviewers than the quorum, so it can't be accepted until more are asked,
+// or "" otherwise
+func quorumWarning(name string, a *adr.ADR, quorum int) string {
+	if len(a.Reviews) == 0 || len(a.Reviews) >= quorum {
+		return ""
+	}
+	return fmt.Sprintf("%s has %d reviewer(s) but review.quorum requires %d approvals; ask more with 'stamp review request'",
+		name, len(a.Reviews), quorum)
+}
+
+// reviewerNames returns the names of every reviewer of the ADR
+func reviewerNames(a *adr.ADR) []string {
+	names := make([]string, len(a.Reviews))
+	for i, r := range a.Reviews {
+		names[i] = r.Reviewer
+	}
+	return names
+}
+
+// approvalCount formats the approvals of an ADR against the quorum, like "1/2"
+func approvalCount(a *adr.ADR, quorum int) string {
+	if quorum <= 0 {
+		quorum = len(a.Reviews)
+	}
+	return fmt.Sprintf("%d/%d", len(a.Reviewers(adr.VerdictApproved)), quorum)
+}
+
+// printReviews prints the verdict of every reviewer of an ADR
+func printReviews(a *adr.ADR, quorum int) {
+	fmt.Fprintln(ui.Stdout, ui.Bold("ADR "+formatNumber(a.Number)+": "+a.Title)+"  "+ui.RenderStatus(a.Status))
+	if len(a.Reviews) == 0 {
+		fmt.Fprintln(ui.Stdout, ui.Muted("No reviewers yet"))
+		return
+	}
+
+	rows := make([][]string, len(a.Reviews))
+	for i, r := range a.Reviews {
+		date := ""
+		if !r.Date.IsZero() {
+			date = r.Date.Format("2006-01-02")
+		}
+		rows[i] = []string{r.Reviewer, renderVerdict(r.Verdict), date, r.Comment}
+	}
+	fmt.Fprintln(ui.Stdout, listTable([]string{"REVIEWER", "VERDICT", "DATE", "COMMENT"}, rows))
+	fmt.Fprintln(ui.Stdout, ui.Muted(approvalCount(a, quorum)+" approvals"))
+}
+
+// renderVerdict colors a verdict like the messages of the same outcome
+func renderVerdict(v adr.Verdict) string {
+	switch v {
+	case adr.VerdictApproved:
+		return ui.SuccessStyle.Render(string(v))
+	case adr.VerdictRejected:
+		return ui.ErrorStyle.Render(string(v))
+	}
+	return ui.Muted(string(v))
+}
+
+func init() {
+	reviewRequestCmd.Flags().StringSliceVar(&reviewReviewers, "reviewers", nil, "Comma-separated names of the reviewers")
+	for _, c := range []*cobra.Command{reviewApproveCmd, reviewRejectCmd} {
+		c.Flags().StringVarP(&reviewComment, "comment", "m", "", "Comment recorded with the verdict")
+		c.Flags().StringVar(&reviewAs, "as", "", "Reviewer name (default: the author setting)")
+	}
+	reviewCmd.AddCommand(reviewRequestCmd, reviewApproveCmd, reviewRejectCmd, reviewStatusCmd)
+	rootCmd.AddCommand(reviewCmd)
+}
@@ -1,7 +1,23 @@
 package cmd
 
 import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/config"
+	"github.com/stef16robbe/stamp/internal/gitfs"
+	"github.com/stef16robbe/stamp/internal/ui"
 )
 
 // Version is set via ldflags at build time
@@ -13,10 +29,392 @@ var rootCmd = &cobra.Command{
 	Long:  `Stamp is a CLI tool for creating and managing Architecture Decision Records (ADRs).`,
 }
 
+// revision is the git revision to read ADRs from, set with --rev
+var revision string
+
+// collectionName is the ADR collection to work on, set with --collection
+var collectionName string
+
+// configFile is an explicit project configuration file, set with --config
+var configFile string
+
+// colorMode is when output is styled, set with --color
+var colorMode string
+
+// revisionAnnotation marks commands that only read ADRs and support --rev
+const revisionAnnotation = "stamp/rev"
+
 func init() {
 	rootCmd.Version = Version
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Project configuration file (default: .stamp.yaml in this or a parent directory)")
+	rootCmd.PersistentFlags().StringVar(&collectionName, "collection", "", "ADR collection to use (default: chosen from the current directory)")
+	rootCmd.PersistentFlags().StringVar(&revision, "rev", "", "Read ADRs as they were at a git commit, tag or branch")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "When to use colors and Unicode icons: auto, always or never")
+	_ = rootCmd.RegisterFlagCompletionFunc("collection", completeCollections)
+	_ = rootCmd.RegisterFlagCompletionFunc("color", cobra.FixedCompletions(ui.ColorModes, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := ui.SetColor(colorMode); err != nil {
+			return err
+		}
+		// Commands outside a project still use the user's theme. A broken
+		// user configuration is reported by the commands that load it.
+		if s, err := config.LoadSettings(); err == nil {
+			_ = applyTheme(s)
+		}
+		if revision != "" && cmd.Annotations[revisionAnnotation] == "" {
+			return fmt.Errorf("'stamp %s' does not support --rev", cmd.Name())
+		}
+		return nil
+	}
 }
 
 func Execute() error {
+	defer closeTrees()
 	return rootCmd.Execute()
 }
+
+// trees are the git trees opened for --rev, closed when the command is done
+var trees []*gitfs.FS
+
+func closeTrees() {
+	for _, tree := range trees {
+		_ = tree.Close()
+	}
+	trees = nil
+}
+
+// settings are the preferences of the loaded configuration. Until a command
+// loads it, only the built-in defaults apply.
+var settings = config.DefaultSettings()
+
+// loadConfig loads the project configuration, from --config if given
+func loadConfig() (*config.Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	settings = cfg.Settings
+	if err := applyTheme(settings); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyTheme makes the theme named in s the current one
+func applyTheme(s config.Settings) error {
+	t, err := resolveTheme(s.Theme, s.Themes, nil)
+	if err != nil {
+		return err
+	}
+	ui.SetTheme(t)
+	return nil
+}
+
+// resolveTheme returns a custom or built-in theme. Custom themes are built on
+// their base theme, which may be custom too.
+func resolveTheme(name string, custom map[string]config.ThemeConfig, seen []string) (*ui.Theme, error) {
+	c, ok := custom[name]
+	if !ok {
+		t, err := ui.LookupTheme(name)
+		if err != nil && len(custom) > 0 {
+			return nil, fmt.Errorf("%w, or custom: %s", err, strings.Join(slices.Sorted(maps.Keys(custom)), ", "))
+		}
+		return t, err
+	}
+	if slices.Contains(seen, name) {
+		return nil, fmt.Errorf("theme %s is its own base", name)
+	}
+
+	baseName := cmp.Or(c.Base, ui.DefaultTheme)
+	var base *ui.Theme
+	var err error
+	if baseName == name {
+		// A custom theme may adjust the built-in theme of the same name
+		base, err = ui.LookupTheme(name)
+	} else {
+		base, err = resolveTheme(baseName, custom, append(seen, name))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: %w", name, err)
+	}
+
+	o := &ui.Theme{
+		Statuses:  make(map[adr.Status]ui.StatusColors),
+		Success:   c.Success,
+		Warning:   c.Warning,
+		Error:     c.Error,
+		Muted:     c.Muted,
+		Accent:    c.Accent,
+		OnAccent:  c.OnAccent,
+		Highlight: c.Highlight,
+	}
+	for key, colors := range c.Statuses {
+		status, err := adr.ParseStatus(key)
+		if err != nil {
+			return nil, fmt.Errorf("theme %s: %w", name, err)
+		}
+		o.Statuses[status] = ui.StatusColors{Fill: colors.Fill, Text: colors.Text, Stroke: colors.Stroke}
+	}
+	return base.Override(o), nil
+}
+
+// openRepository opens the ADRs of the project for commands that only read
+// them. With --rev, the ADR directory is read from the git object database
+// as it was at that revision.
+func openRepository() (adr.Repository, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	col, err := resolveCollection(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return openCollection(cfg, col)
+}
+
+// openRef opens the repository holding the ADR referenced by ref, like
+// openRepository, and returns the ADR's number
+func openRef(ref string) (adr.Repository, int, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	col, num, err := resolveADRRef(cfg, ref)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	repo, err := openCollection(cfg, col)
+	if err != nil {
+		return nil, 0, err
+	}
+	return repo, num, nil
+}
+
+// openCollection opens the ADRs of col, from --rev if given
+func openCollection(cfg *config.Config, col *config.ResolvedCollection) (adr.Repository, error) {
+	if revision == "" {
+		return col.Store(), nil
+	}
+
+	projectRoot, err := cfg.Root()
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(projectRoot, col.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := gitfs.New(projectRoot, revision, filepath.ToSlash(rel))
+	if err != nil {
+		return nil, err
+	}
+	trees = append(trees, tree)
+	repo := adr.NewFSRepository(tree)
+	repo.Scheme = col.Scheme
+	return repo, nil
+}
+
+// linkedStore returns the store of col linked to every other collection, so
+// renaming one of its ADRs rewrites links to it from the whole project
+func linkedStore(cfg *config.Config, col *config.ResolvedCollection) (*adr.Store, error) {
+	cols, err := cfg.ResolveCollections()
+	if err != nil {
+		return nil, err
+	}
+	store := col.Store()
+	for _, other := range cols {
+		if other.Directory != col.Directory {
+			store.Linked = append(store.Linked, other.Store())
+		}
+	}
+	return store, nil
+}
+
+// active is the collection the current command works on. Until a command
+// resolves it, ADRs are formatted with the default scheme.
+var active = &config.ResolvedCollection{Prefix: config.DefaultPrefix, Scheme: adr.DefaultScheme}
+
+// resolveCollection returns the collection selected with --collection,
+// $STAMP_COLLECTION or the current directory, and makes it the active one
+func resolveCollection(cfg *config.Config) (*config.ResolvedCollection, error) {
+	name := collectionName
+	if name == "" {
+		name = os.Getenv("STAMP_COLLECTION")
+	}
+	col, err := cfg.ResolveCollection(name)
+	if err != nil {
+		return nil, err
+	}
+	active = col
+	return col, nil
+}
+
+// formatNumber formats an ADR number of the active collection, like "0001"
+func formatNumber(num int) string {
+	return active.Scheme.FormatNumber(num)
+}
+
+// parseDate parses a date given on the command line
+func parseDate(s string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date: %s (expected YYYY-MM-DD)", s)
+	}
+	return t, nil
+}
+
+// nodeID returns the identifier of an ADR numbered with scheme in generated
+// diagrams, like "ADR1"
+func nodeID(scheme adr.Scheme, num int) string {
+	return fmt.Sprintf("%s%d", scheme.Prefix, num)
+}
+
+// resolveRef parses an ADR reference: a plain number refers to the current
+// collection, "<PREFIX>-<number>" and "<collection>:<number>" to another one
+func resolveRef(cfg *config.Config, current *config.ResolvedCollection, ref string) (*config.ResolvedCollection, int, error) {
+	if num, err := strconv.Atoi(ref); err == nil {
+		return current, num, nil
+	}
+
+	name, numStr, ok := strings.Cut(ref, ":")
+	if ok {
+		num, err := strconv.Atoi(numStr)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid ADR number: %s", ref)
+		}
+		col, err := cfg.ResolveCollection(name)
+		if err != nil {
+			return nil, 0, err
+		}
+		return col, num, nil
+	}
+
+	i := strings.LastIndex(ref, "-")
+	if i <= 0 {
+		return nil, 0, fmt.Errorf("invalid ADR number: %s", ref)
+	}
+	prefix, numStr := ref[:i], ref[i+1:]
+	num, err := strconv.Atoi(numStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid ADR number: %s", ref)
+	}
+
+	if strings.EqualFold(prefix, current.Prefix) {
+		return current, num, nil
+	}
+	cols, err := cfg.ResolveCollections()
+	if err != nil {
+		return nil, 0, err
+	}
+	var matches []*config.ResolvedCollection
+	for _, col := range cols {
+		if strings.EqualFold(prefix, col.Prefix) {
+			matches = append(matches, col)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, 0, fmt.Errorf("no collection uses the ID prefix %s", prefix)
+	case 1:
+		return matches[0], num, nil
+	default:
+		return nil, 0, fmt.Errorf("several collections use the ID prefix %s; use <collection>:<number> instead", prefix)
+	}
+}
+
+// resolveADRRef resolves the ADR argument of a command like resolveRef,
+// relative to the current collection. The collection of the ADR becomes the
+// active one.
+func resolveADRRef(cfg *config.Config, ref string) (*config.ResolvedCollection, int, error) {
+	current, err := resolveCollection(cfg)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	col, num, err := resolveRef(cfg, current, ref)
+	if err != nil {
+		return nil, 0, err
+	}
+	active = col
+	return col, num, nil
+}
+
+// findADR looks up an ADR by number, reporting numbers shared by several files
+func findADR(repo adr.Repository, num int) (*adr.ADR, error) {
+	a, err := adr.FindByNumber(repo, num)
+	var dup *adr.DuplicateNumberError
+	if errors.As(err, &dup) {
+		return nil, err
+	}
+	var d *adr.Diagnostic
+	if errors.As(err, &d) {
+		return nil, fmt.Errorf("ADR %s could not be read: %w", formatNumber(num), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ADR %s not found", formatNumber(num))
+	}
+	return a, nil
+}
+
+// editorCommand returns the command that opens path in the configured
+// editor, falling back to $VISUAL and $EDITOR
+func editorCommand(path string) (*exec.Cmd, error) {
+	editor := settings.Editor
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no editor configured (set editor in %s, $VISUAL or $EDITOR)", config.UserConfigFileName)
+	}
+
+	c := exec.Command(args[0], append(args[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c, nil
+}
+
+// warnSkipped prints a warning to stderr for every ADR file that could not be
+// loaded and every number shared by several files
+func warnSkipped(diagnostics []*adr.Diagnostic) {
+	for _, d := range diagnostics {
+		if d.Duplicate != nil {
+			fmt.Fprintln(ui.Stderr, ui.Warning(d.Error()))
+			continue
+		}
+		fmt.Fprintln(ui.Stderr, ui.Warning("Skipped "+d.Error()))
+	}
+}
+
+// writeOutput writes the output of a command to path, or to stdout if path is
+// empty. Raw output, such as images, must not pass through the color filter.
+func writeOutput(output, path string, raw bool) error {
+	if path == "" {
+		if raw {
+			fmt.Print(output)
+		} else {
+			fmt.Fprint(ui.Stdout, output)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Fprintln(ui.Stderr, ui.Success("Wrote "+ui.Muted(path)))
+	return nil
+}
@@ -2,53 +2,73 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
+	"os"
 	"strings"
 
 	"charm.land/glamour/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/spf13/cobra"
-	"github.com/stef16robbe/stamp/internal/adr"
-	"github.com/stef16robbe/stamp/internal/config"
 	"github.com/stef16robbe/stamp/internal/ui"
 )
 
+var (
+	showRaw     bool
+	showNoFrame bool
+	showNoPager bool
+)
+
 var showCmd = &cobra.Command{
 	Use:   "show <number>",
 	Short: "Show an ADR",
-	Long:  `Displays an Architecture Decision Record with rendered markdown.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Displays an Architecture Decision Record with rendered markdown, wrapped to
+the width of the terminal. ADRs taller than the terminal open in $PAGER
+(less by default).`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeArgs(completeADRNumbers),
+	Annotations:       map[string]string{revisionAnnotation: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		num, err := strconv.Atoi(args[0])
-		if err != nil {
-			return fmt.Errorf("invalid ADR number: %s", args[0])
-		}
-
-		cfg, err := config.Load()
+		repo, num, err := openRef(args[0])
 		if err != nil {
 			return err
 		}
 
-		dir, err := cfg.ADRDirectory()
+		a, err := findADR(repo, num)
 		if err != nil {
 			return err
 		}
 
-		store := adr.NewStore(dir)
+		if showRaw {
+			fmt.Print(a.FormatMarkdown(active.Scheme))
+			return nil
+		}
 
-		a, err := store.FindByNumber(num)
-		if err != nil {
-			return fmt.Errorf("ADR %04d not found", num)
+		// Fill the terminal, less the border and padding of the frame
+		width, _ := ui.Size(os.Stdout)
+		if !showNoFrame {
+			width -= 4
+		}
+		// $GLAMOUR_STYLE picks a style, otherwise it follows the background
+		style := glamour.WithEnvironmentConfig()
+		if os.Getenv("GLAMOUR_STYLE") == "" {
+			switch {
+			case !ui.HasColor():
+				style = glamour.WithStandardStyle("notty")
+			case ui.HasDarkBackground():
+				style = glamour.WithStandardStyle("dark")
+			default:
+				style = glamour.WithStandardStyle("light")
+			}
 		}
 
 		renderer, err := glamour.NewTermRenderer(
-			glamour.WithWordWrap(80),
+			style,
+			glamour.WithWordWrap(max(width, 40)),
 		)
 		if err != nil {
 			return fmt.Errorf("failed to create renderer: %w", err)
 		}
 
-		output, err := renderer.Render(a.ToMarkdown())
+		output, err := renderer.Render(a.FormatMarkdown(active.Scheme))
 		if err != nil {
 			return fmt.Errorf("failed to render ADR: %w", err)
 		}
@@ -57,10 +77,10 @@ var showCmd = &cobra.Command{
 		output = strings.TrimRight(output, "\n")
 
 		// Create title for the frame
-		title := fmt.Sprintf(" ADR %04d ", a.Number)
+		title := " ADR " + formatNumber(a.Number) + " "
 		titleStyle := lipgloss.NewStyle().
-			Background(ui.Cyan).
-			Foreground(lipgloss.Color("0")).
+			Background(ui.AccentColor).
+			Foreground(ui.OnAccentColor).
 			Bold(true).
 			Padding(0, 1)
 
@@ -68,21 +88,28 @@ var showCmd = &cobra.Command{
 
 		header := titleStyle.Render(title) + " " + statusBadge
 
-		// Frame the content
-		frameStyle := lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ui.Gray).
-			Padding(0, 1)
-
-		framedContent := frameStyle.Render(output)
+		if !showNoFrame {
+			// Frame the content
+			frameStyle := lipgloss.NewStyle().
+				Border(ui.FrameBorder).
+				BorderForeground(ui.MutedColor).
+				Padding(0, 1)
 
-		fmt.Println(header)
-		fmt.Println(framedContent)
+			output = frameStyle.Render(output)
+		}
 
-		return nil
+		content := header + "\n" + output
+		if showNoPager {
+			fmt.Fprintln(ui.Stdout, content)
+			return nil
+		}
+		return ui.Page(content)
 	},
 }
 
 func init() {
+	showCmd.Flags().BoolVar(&showRaw, "raw", false, "Print the Markdown source instead of rendering it")
+	showCmd.Flags().BoolVar(&showNoFrame, "no-frame", false, "Don't draw a border around the ADR")
+	showCmd.Flags().BoolVar(&showNoPager, "no-pager", false, "Print the ADR directly, even when it is taller than the terminal")
 	rootCmd.AddCommand(showCmd)
 }
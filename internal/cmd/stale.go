@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/config"
+	"github.com/stef16robbe/stamp/internal/ui"
+)
+
+var (
+	staleDays     int
+	staleFormat   string
+	staleExitCode bool
+)
+
+// staleFormats are the output formats of 'stamp stale'
+var staleFormats = []string{"text", "json"}
+
+// staleADR is an ADR to revisit, as emitted by --format json
+type staleADR struct {
+	ID           string          `json:"id"`
+	Number       int             `json:"number"`
+	Title        string          `json:"title"`
+	Status       string          `json:"status"`
+	Filename     string          `json:"filename"`
+	Reason       adr.StaleReason `json:"reason"`
+	LastActivity string          `json:"last_activity"`
+	ReviewBy     string          `json:"review_by,omitempty"`
+	// Days is how long the ADR went without activity, or is past its review date
+	Days int `json:"days"`
+}
+
+var staleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "List ADRs to revisit",
+	Long: `Lists the decisions that need attention:
+
+  - draft and proposed ADRs without activity (creation, status change or
+    review) for more than stale.days days in .stamp.yaml (default 30)
+  - accepted ADRs whose review date, set with --review-by on 'stamp new' or
+    'stamp status', has come
+
+With --exit-code, exits with a non-zero status when any ADR is stale, so a
+scheduled CI job can send a reminder.
+
+Examples:
+  stamp stale
+  stamp stale --days 14
+  stamp stale --format json --exit-code`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{revisionAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(staleFormats, staleFormat) {
+			return fmt.Errorf("invalid format: %s (valid: %s)", staleFormat, strings.Join(staleFormats, ", "))
+		}
+		if cmd.Flags().Changed("days") && staleDays < 1 {
+			return fmt.Errorf("invalid number of days: %d (expected at least 1)", staleDays)
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		col, err := resolveCollection(cfg)
+		if err != nil {
+			return err
+		}
+		repo, err := openCollection(cfg, col)
+		if err != nil {
+			return err
+		}
+		days := cmp.Or(cfg.Stale.Days, config.DefaultStaleDays)
+		if cmd.Flags().Changed("days") {
+			days = staleDays
+		}
+
+		adrs, diagnostics, err := repo.List()
+		if err != nil {
+			return fmt.Errorf("failed to list ADRs: %w", err)
+		}
+		warnSkipped(diagnostics)
+
+		stale := findStale(adrs, time.Now(), days)
+
+		if staleFormat == "json" {
+			data, err := json.MarshalIndent(stale, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(ui.Stdout, string(data))
+		} else {
+			printStale(stale, days)
+		}
+
+		if staleExitCode && len(stale) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("found %d stale ADR(s)", len(stale))
+		}
+		return nil
+	},
+}
+
+// findStale returns the ADRs to revisit on the day of now, in number order
+func findStale(adrs []*adr.ADR, now time.Time, days int) []staleADR {
+	stale := []staleADR{}
+	for _, a := range adrs {
+		reason, ok := a.Stale(now, days)
+		if !ok {
+			continue
+		}
+		s := staleADR{
+			ID:           active.FormatID(a.Number),
+			Number:       a.Number,
+			Title:        a.Title,
+			Status:       string(a.Status),
+			Filename:     a.Filename,
+			Reason:       reason,
+			LastActivity: a.LastActivity().Format("2006-01-02"),
+			Days:         adr.DaysBetween(a.LastActivity(), now),
+		}
+		if !a.ReviewBy.IsZero() {
+			s.ReviewBy = a.ReviewBy.Format("2006-01-02")
+		}
+		if reason == adr.StaleReviewDue {
+			s.Days = adr.DaysBetween(a.ReviewBy, now)
+		}
+		stale = append(stale, s)
+	}
+	return stale
+}
+
+// printStale prints the ADRs to revisit as a table
+func printStale(stale []staleADR, days int) {
+	if len(stale) == 0 {
+		fmt.Fprintln(ui.Stdout, ui.Success("No stale ADRs (drafts and proposals are stale after "+strconv.Itoa(days)+" days without activity)"))
+		return
+	}
+
+	rows := make([][]string, len(stale))
+	for i, s := range stale {
+		why := fmt.Sprintf("No activity for %d days, since %s", s.Days, s.LastActivity)
+		if s.Reason == adr.StaleReviewDue {
+			why = "Review due on " + s.ReviewBy
+			if s.Days > 0 {
+				why += fmt.Sprintf(", %d days ago", s.Days)
+			}
+		}
+		rows[i] = []string{formatNumber(s.Number), s.Title, ui.RenderStatus(adr.Status(s.Status)), why}
+	}
+	fmt.Fprintln(ui.Stdout, listTable([]string{"NUM", "TITLE", "STATUS", "WHY"}, rows))
+}
+
+func init() {
+	staleCmd.Flags().IntVar(&staleDays, "days", 0, "Days a draft or proposed ADR may go without activity (default: stale.days, or 30)")
+	staleCmd.Flags().StringVarP(&staleFormat, "format", "f", "text", "Output format: text or json")
+	staleCmd.Flags().BoolVar(&staleExitCode, "exit-code", false, "Exit with a non-zero status when any ADR is stale")
+	_ = staleCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(staleFormats, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.AddCommand(staleCmd)
+}
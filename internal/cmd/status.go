@@ -2,62 +2,108 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stef16robbe/stamp/internal/adr"
-	"github.com/stef16robbe/stamp/internal/config"
 	"github.com/stef16robbe/stamp/internal/ui"
 )
 
+var (
+	statusReason   string
+	statusAuthor   string
+	statusReviewBy string
+)
+
 var statusCmd = &cobra.Command{
 	Use:   "status <number> <status>",
 	Short: "Update the status of an ADR",
 	Long: `Updates the status of an Architecture Decision Record.
 
-Valid statuses: draft, proposed, accepted, deprecated, superseded, rejected`,
-	Args: cobra.ExactArgs(2),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		num, err := strconv.Atoi(args[0])
-		if err != nil {
-			return fmt.Errorf("invalid ADR number: %s", args[0])
-		}
+Each change is recorded with its date, author and optional reason in the ADR's
+"Status history" section, shown by 'stamp show' and queried with
+'stamp list --changed-since'.
+
+Valid statuses: draft, proposed, accepted, deprecated, superseded, rejected
 
+Accepted ADRs with a --review-by date are reported by 'stamp stale' once it
+comes.
+
+Examples:
+  stamp status 12 accepted --reason "Approved at the architecture review"
+  stamp status 12 accepted --review-by 2027-10-01`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeArgs(completeADRNumbers, completeStatuses),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		newStatus, err := adr.ParseStatus(args[1])
 		if err != nil {
 			return err
 		}
 
-		cfg, err := config.Load()
+		var reviewBy time.Time
+		if statusReviewBy != "" {
+			if reviewBy, err = parseDate(statusReviewBy); err != nil {
+				return err
+			}
+		}
+
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
 
-		dir, err := cfg.ADRDirectory()
+		col, num, err := resolveADRRef(cfg, args[0])
 		if err != nil {
 			return err
 		}
 
-		store := adr.NewStore(dir)
+		store := col.Store()
 
-		a, err := store.FindByNumber(num)
-		if err != nil {
-			return fmt.Errorf("ADR %04d not found", num)
+		author := settings.Author
+		if cmd.Flags().Changed("author") {
+			author = statusAuthor
 		}
 
-		oldStatus := a.Status
-		a.Status = newStatus
+		var oldStatus adr.Status
+		err = store.WithLock(func() error {
+			a, err := findADR(store, num)
+			if err != nil {
+				return err
+			}
+
+			oldStatus = a.Status
+			changed := a.SetStatus(newStatus, time.Now(), author, statusReason)
+			if !reviewBy.IsZero() {
+				a.ReviewBy = reviewBy
+			} else if !changed {
+				return nil
+			}
 
-		if err := store.Save(a); err != nil {
-			return fmt.Errorf("failed to save ADR: %w", err)
+			if err := store.Save(a); err != nil {
+				return fmt.Errorf("failed to save ADR: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 
-		fmt.Println(ui.Success(fmt.Sprintf("Updated ADR %04d: ", num)) + ui.RenderStatusTransition(oldStatus, newStatus))
+		if oldStatus != newStatus {
+			fmt.Fprintln(ui.Stdout, ui.Success("Updated ADR "+formatNumber(num)+": ")+ui.RenderStatusTransition(oldStatus, newStatus))
+		} else if reviewBy.IsZero() {
+			fmt.Fprintln(ui.Stdout, ui.Warning("ADR "+formatNumber(num)+" is already ")+ui.RenderStatus(newStatus))
+		}
+		if !reviewBy.IsZero() {
+			fmt.Fprintln(ui.Stdout, ui.Success("ADR "+formatNumber(num)+" is due for review on "+ui.Bold(reviewBy.Format("2006-01-02"))))
+		}
 
 		return nil
 	},
 }
 
 func init() {
+	statusCmd.Flags().StringVarP(&statusReason, "reason", "r", "", "Why the status changed, recorded in the status history")
+	statusCmd.Flags().StringVar(&statusAuthor, "author", "", "Author recorded in the status history (default: the author setting)")
+	statusCmd.Flags().StringVar(&statusReviewBy, "review-by", "", "Date to revisit the decision by (YYYY-MM-DD)")
 	rootCmd.AddCommand(statusCmd)
 }
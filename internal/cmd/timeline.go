@@ -0,0 +1,497 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/spf13/cobra"
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/ui"
+)
+
+var (
+	timelineFormat    string
+	timelineGroup     string
+	timelineSince     string
+	timelineNoHistory bool
+)
+
+// statusChange is a status transition found in the git history of an ADR
+type statusChange struct {
+	Date     time.Time
+	Filename string
+	From     adr.Status
+	To       adr.Status
+}
+
+// timelineRow is a single entry of the timeline: either an ADR being
+// recorded (Change is nil) or one of its status changes
+type timelineRow struct {
+	Date   time.Time
+	ADR    *adr.ADR
+	Change *statusChange
+}
+
+// gitStatusHistory returns the status transitions recorded in the git history
+// of dir up to rev (HEAD if empty), oldest first. Renames are followed, so
+// the changes of an ADR that was retitled or renumbered are reported under
+// its current filename.
+func gitStatusHistory(dir, rev string) ([]statusChange, error) {
+	args := []string{"-C", dir, "log", "--no-color", "--format=%x00%aI", "-p", "-M", "--unified=0"}
+	if rev != "" {
+		args = append(args, "--end-of-options", rev)
+	}
+	args = append(args, "--", ".")
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseStatusLog(out)
+}
+
+// parseStatusLog reads the output of gitStatusHistory's git log. It relies on
+// the status being the only word on its line, which is how stamp writes it.
+func parseStatusLog(out []byte) ([]statusChange, error) {
+	var changes []statusChange
+	var date time.Time
+	var filename string
+	var removed adr.Status
+
+	// git log lists the newest commit first, so by the time an older commit
+	// is read, renames maps each of its filenames to the current one
+	renames := make(map[string]string)
+	current := func(name string) string {
+		if renamed, ok := renames[name]; ok {
+			return renamed
+		}
+		return name
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\x00"):
+			date, _ = time.Parse(time.RFC3339, strings.TrimPrefix(line, "\x00"))
+		case strings.HasPrefix(line, "diff --git "):
+			_, b, _ := strings.Cut(line, " b/")
+			filename = current(filepath.Base(b))
+			removed = ""
+		case strings.HasPrefix(line, "rename from "):
+			renames[filepath.Base(strings.TrimPrefix(line, "rename from "))] = filename
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			continue
+		case strings.HasPrefix(line, "-"):
+			if status, err := adr.ParseStatus(line[1:]); err == nil {
+				removed = status
+			}
+		case strings.HasPrefix(line, "+"):
+			status, err := adr.ParseStatus(line[1:])
+			if err != nil || removed == "" || removed == status {
+				continue
+			}
+			changes = append(changes, statusChange{Date: date, Filename: filename, From: removed, To: status})
+			removed = ""
+		}
+	}
+
+	slices.Reverse(changes)
+
+	return changes, scanner.Err()
+}
+
+// timelinePeriod returns the heading of the group a date belongs to
+func timelinePeriod(t time.Time, group string) string {
+	switch group {
+	case "quarter":
+		return fmt.Sprintf("%d Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	case "year":
+		return fmt.Sprintf("%d", t.Year())
+	default:
+		return t.Format("January 2006")
+	}
+}
+
+// buildTimeline returns the rows of the timeline sorted by date. The status
+// history recorded in an ADR takes the place of the changes found in git.
+func buildTimeline(adrs []*adr.ADR, changes []statusChange, since time.Time) []timelineRow {
+	byFilename := make(map[string]*adr.ADR, len(adrs))
+	var rows []timelineRow
+	for _, a := range adrs {
+		if !a.Date.Before(since) {
+			rows = append(rows, timelineRow{Date: a.Date, ADR: a})
+		}
+		if len(a.History) == 0 {
+			byFilename[a.Filename] = a
+			continue
+		}
+		for _, h := range a.History {
+			if h.Date.IsZero() || h.Date.Before(since) {
+				continue
+			}
+			c := &statusChange{Date: h.Date, Filename: a.Filename, From: h.From, To: h.To}
+			rows = append(rows, timelineRow{Date: c.Date, ADR: a, Change: c})
+		}
+	}
+
+	for i := range changes {
+		c := &changes[i]
+		a, ok := byFilename[c.Filename]
+		if !ok || c.Date.Before(since) {
+			continue
+		}
+		rows = append(rows, timelineRow{Date: c.Date, ADR: a, Change: c})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		di := rows[i].Date.Format("2006-01-02")
+		dj := rows[j].Date.Format("2006-01-02")
+		if di != dj {
+			return di < dj
+		}
+		if (rows[i].Change == nil) != (rows[j].Change == nil) {
+			return rows[i].Change == nil
+		}
+		return rows[i].ADR.Number < rows[j].ADR.Number
+	})
+
+	return rows
+}
+
+// supersessionLanes draws the gutter connecting superseding ADRs to the ADRs
+// they supersede. lines holds the row index of every printed line, or -1 for
+// headings; the result has one gutter string per line.
+func supersessionLanes(rows []timelineRow, lines []int) []string {
+	recorded := make(map[int]int) // ADR number -> line of its recorded row
+	for line, row := range lines {
+		if row >= 0 && rows[row].Change == nil {
+			recorded[rows[row].ADR.Number] = line
+		}
+	}
+
+	type arc struct{ from, to, top, bottom int }
+	var arcs []arc
+	for _, row := range rows {
+		if row.Change != nil {
+			continue
+		}
+		for _, link := range parseLinks(row.ADR) {
+			if link.Relation != "Supersedes" {
+				continue
+			}
+			from, okFrom := recorded[link.Source]
+			to, okTo := recorded[link.Target]
+			if !okFrom || !okTo || from == to {
+				continue
+			}
+			arcs = append(arcs, arc{from: from, to: to, top: min(from, to), bottom: max(from, to)})
+		}
+	}
+
+	if len(arcs) == 0 {
+		return make([]string, len(lines))
+	}
+
+	// Assign each arc to the first lane that is free for its whole span
+	sort.SliceStable(arcs, func(i, j int) bool { return arcs[i].top < arcs[j].top })
+	var laneEnds []int
+	lanes := make([]int, len(arcs))
+	for i, a := range arcs {
+		lane := -1
+		for l, end := range laneEnds {
+			if end < a.top {
+				lane = l
+				break
+			}
+		}
+		if lane < 0 {
+			lane = len(laneEnds)
+			laneEnds = append(laneEnds, 0)
+		}
+		laneEnds[lane] = a.bottom
+		lanes[i] = lane
+	}
+
+	width := 2*len(laneEnds) + 1
+	grid := make([][]rune, len(lines))
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", width))
+	}
+
+	horizontal := func(line, from int) {
+		for x := from; x < width; x++ {
+			if grid[line][x] == '│' {
+				grid[line][x] = '┼'
+			} else if grid[line][x] == ' ' {
+				grid[line][x] = '─'
+			}
+		}
+	}
+
+	for i, a := range arcs {
+		x := 2 * lanes[i]
+		for line := a.top + 1; line < a.bottom; line++ {
+			if grid[line][x] == '─' {
+				grid[line][x] = '┼'
+			} else {
+				grid[line][x] = '│'
+			}
+		}
+		grid[a.top][x] = '╭'
+		grid[a.bottom][x] = '╰'
+		horizontal(a.top, x+1)
+		horizontal(a.bottom, x+1)
+		grid[a.to][width-1] = '▶'
+	}
+
+	style := lipgloss.NewStyle().Foreground(ui.HighlightColor)
+	gutters := make([]string, len(lines))
+	for i, g := range grid {
+		gutters[i] = style.Render(ui.Glyphs(string(g))) + " "
+	}
+	return gutters
+}
+
+func renderTimeline(rows []timelineRow, group string) string {
+	// Lines to print: -1 for a period heading, otherwise the row index
+	var lines []int
+	var headings []string
+	period := ""
+	for i, row := range rows {
+		if p := timelinePeriod(row.Date, group); p != period {
+			period = p
+			lines = append(lines, -1)
+			headings = append(headings, p)
+		}
+		lines = append(lines, i)
+	}
+
+	gutters := supersessionLanes(rows, lines)
+
+	badgeWidth := 0
+	for _, row := range rows {
+		badgeWidth = max(badgeWidth, lipgloss.Width(timelineBadge(row)))
+	}
+
+	var sb strings.Builder
+	heading := 0
+	for i, row := range lines {
+		if row < 0 {
+			if i > 0 {
+				sb.WriteString(gutters[i] + "\n")
+			}
+			sb.WriteString(gutters[i] + ui.Bold(headings[heading]) + "\n")
+			heading++
+			continue
+		}
+
+		r := rows[row]
+		badge := timelineBadge(r)
+		padding := strings.Repeat(" ", badgeWidth-lipgloss.Width(badge))
+		title := r.ADR.Title
+		if r.Change != nil {
+			title = ui.Muted(title)
+		}
+		fmt.Fprintf(&sb, "%s  %s  %s  %s%s  %s\n",
+			gutters[i], ui.Muted(r.Date.Format("2006-01-02")), formatNumber(r.ADR.Number), badge, padding, title)
+	}
+
+	return sb.String()
+}
+
+func timelineBadge(row timelineRow) string {
+	if row.Change != nil {
+		return ui.RenderStatusTransition(row.Change.From, row.Change.To)
+	}
+	return ui.RenderStatus(row.ADR.Status)
+}
+
+// mermaidText strips characters with special meaning in Mermaid timelines and gantt charts
+func mermaidText(s string) string {
+	return strings.NewReplacer(":", " ", "#", "", ";", ",").Replace(s)
+}
+
+func generateMermaidTimeline(rows []timelineRow, group string) string {
+	var sb strings.Builder
+
+	sb.WriteString("timeline\n")
+	sb.WriteString("    title Architecture Decisions\n")
+
+	period := ""
+	for _, row := range rows {
+		if p := timelinePeriod(row.Date, group); p != period {
+			period = p
+			fmt.Fprintf(&sb, "    section %s\n", p)
+		}
+		event := fmt.Sprintf("%s %s (%s)", formatNumber(row.ADR.Number), mermaidText(row.ADR.Title), row.ADR.Status)
+		if row.Change != nil {
+			event = fmt.Sprintf("%s %s %s %s", formatNumber(row.ADR.Number), row.Change.From, ui.Arrow, row.Change.To)
+		}
+		fmt.Fprintf(&sb, "        %s : %s\n", row.Date.Format("2006-01-02"), event)
+	}
+
+	return sb.String()
+}
+
+// statusToGanttTag maps statuses to Mermaid gantt task tags
+var statusToGanttTag = map[adr.Status]string{
+	adr.StatusAccepted:   "active, ",
+	adr.StatusDeprecated: "done, ",
+	adr.StatusSuperseded: "done, ",
+	adr.StatusRejected:   "crit, ",
+}
+
+// generateGantt draws every ADR as a bar from its date until it was
+// superseded (or today, if it is still in effect)
+func generateGantt(adrs []*adr.ADR, rows []timelineRow, group string) string {
+	ends := make(map[int]time.Time)
+	for _, a := range adrs {
+		for _, link := range parseLinks(a) {
+			if link.Relation == "Superseded by" {
+				for _, b := range adrs {
+					if b.Number == link.Target && (ends[a.Number].IsZero() || b.Date.Before(ends[a.Number])) {
+						ends[a.Number] = b.Date
+					}
+				}
+			}
+		}
+	}
+	// Fall back to the date the status changed when there is no link
+	for _, row := range rows {
+		if row.Change != nil && row.Change.To == adr.StatusSuperseded && ends[row.ADR.Number].IsZero() {
+			ends[row.ADR.Number] = row.Date
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("gantt\n")
+	sb.WriteString("    title Architecture Decisions\n")
+	sb.WriteString("    dateFormat YYYY-MM-DD\n")
+	sb.WriteString("    axisFormat %Y-%m\n")
+
+	today := time.Now()
+	period := ""
+	for _, row := range rows {
+		if row.Change != nil {
+			continue
+		}
+		a := row.ADR
+		if p := timelinePeriod(a.Date, group); p != period {
+			period = p
+			fmt.Fprintf(&sb, "    section %s\n", p)
+		}
+		end := ends[a.Number]
+		if end.IsZero() || !end.After(a.Date) {
+			end = today
+		}
+		fmt.Fprintf(&sb, "    %s %s :%sadr%d, %s, %s\n", formatNumber(a.Number), mermaidText(a.Title),
+			statusToGanttTag[a.Status], a.Number, a.Date.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	return sb.String()
+}
+
+var timelineCmd = &cobra.Command{
+	Use:   "timeline",
+	Short: "Show ADRs chronologically",
+	Long: `Lays out ADRs chronologically by date, grouped by month, quarter or year.
+
+Status changes are shown at the date recorded in each ADR's status history or,
+for ADRs without one, at the date they were committed when the ADR directory is
+tracked by git. Arrows in the left gutter point from superseding ADRs to the
+ADRs they supersede.
+
+Examples:
+  stamp timeline                          # Terminal output, grouped by month
+  stamp timeline --group quarter          # Grouped by quarter
+  stamp timeline --since 2026-07-01       # What did we decide this quarter?
+  stamp timeline --format mermaid         # Mermaid timeline diagram
+  stamp timeline --format gantt           # Mermaid gantt chart of ADR lifetimes`,
+	Annotations: map[string]string{revisionAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch timelineGroup {
+		case "month", "quarter", "year":
+		default:
+			return fmt.Errorf("invalid group: %s (valid: month, quarter, year)", timelineGroup)
+		}
+		switch timelineFormat {
+		case "text", "mermaid", "gantt":
+		default:
+			return fmt.Errorf("invalid format: %s (valid: text, mermaid, gantt)", timelineFormat)
+		}
+
+		var since time.Time
+		if timelineSince != "" {
+			var err error
+			if since, err = parseDate(timelineSince); err != nil {
+				return err
+			}
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		col, err := resolveCollection(cfg)
+		if err != nil {
+			return err
+		}
+
+		repo, err := openCollection(cfg, col)
+		if err != nil {
+			return err
+		}
+
+		adrs, diagnostics, err := repo.List()
+		if err != nil {
+			return fmt.Errorf("failed to list ADRs: %w", err)
+		}
+		warnSkipped(diagnostics)
+
+		if len(adrs) == 0 {
+			fmt.Fprintln(ui.Stdout, ui.Warning("No ADRs found. Create one with 'stamp new <title>'"))
+			return nil
+		}
+
+		var changes []statusChange
+		if !timelineNoHistory {
+			// Without git (or outside a repository) the timeline only uses ADR dates
+			changes, _ = gitStatusHistory(col.Directory, revision)
+		}
+
+		rows := buildTimeline(adrs, changes, since)
+		if len(rows) == 0 {
+			fmt.Fprintln(ui.Stdout, ui.Warning("No ADRs found since "+timelineSince))
+			return nil
+		}
+
+		switch timelineFormat {
+		case "text":
+			fmt.Fprint(ui.Stdout, renderTimeline(rows, timelineGroup))
+		case "mermaid":
+			fmt.Fprint(ui.Stdout, generateMermaidTimeline(rows, timelineGroup))
+		case "gantt":
+			fmt.Fprint(ui.Stdout, generateGantt(adrs, rows, timelineGroup))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	timelineCmd.Flags().StringVarP(&timelineFormat, "format", "f", "text", "Output format: text, mermaid or gantt")
+	timelineCmd.Flags().StringVarP(&timelineGroup, "group", "g", "month", "Group by month, quarter or year")
+	timelineCmd.Flags().StringVar(&timelineSince, "since", "", "Only show entries on or after this date (YYYY-MM-DD)")
+	timelineCmd.Flags().BoolVar(&timelineNoHistory, "no-history", false, "Don't read status changes from git history")
+	rootCmd.AddCommand(timelineCmd)
+}
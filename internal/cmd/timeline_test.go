@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/ui"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// gitLog joins the lines of canned 'git log -p' output
+func gitLog(lines ...string) []byte {
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+func TestParseStatusLog(t *testing.T) {
+	tests := []struct {
+		name string
+		log  []byte
+		want []statusChange
+	}{
+		{
+			name: "empty",
+			log:  nil,
+			want: nil,
+		},
+		{
+			name: "status changes oldest first",
+			log: gitLog(
+				"\x002026-03-01T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0002-use-kafka.md b/docs/adr/0002-use-kafka.md",
+				"index ac8e2a6..0325f38 100644",
+				"--- a/docs/adr/0002-use-kafka.md",
+				"+++ b/docs/adr/0002-use-kafka.md",
+				"@@ -7 +7 @@ Date: 2026-01-05",
+				"-Proposed",
+				"+Accepted",
+				"\x002026-02-01T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0002-use-kafka.md b/docs/adr/0002-use-kafka.md",
+				"index b229ec3..ac8e2a6 100644",
+				"--- a/docs/adr/0002-use-kafka.md",
+				"+++ b/docs/adr/0002-use-kafka.md",
+				"@@ -7 +7 @@ Date: 2026-01-05",
+				"-Draft",
+				"+Proposed",
+			),
+			want: []statusChange{
+				{Date: day("2026-02-01").Add(10 * time.Hour), Filename: "0002-use-kafka.md", From: adr.StatusDraft, To: adr.StatusProposed},
+				{Date: day("2026-03-01").Add(10 * time.Hour), Filename: "0002-use-kafka.md", From: adr.StatusProposed, To: adr.StatusAccepted},
+			},
+		},
+		{
+			name: "new files are not status changes",
+			log: gitLog(
+				"\x002026-01-05T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0002-use-kafka.md b/docs/adr/0002-use-kafka.md",
+				"new file mode 100644",
+				"index 0000000..b229ec3",
+				"--- /dev/null",
+				"+++ b/docs/adr/0002-use-kafka.md",
+				"@@ -0,0 +1,19 @@",
+				"+# 2. Use Kafka",
+				"+",
+				"+## Status",
+				"+",
+				"+Draft",
+			),
+			want: nil,
+		},
+		{
+			name: "unchanged status and other lines are ignored",
+			log: gitLog(
+				"\x002026-01-05T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0002-use-kafka.md b/docs/adr/0002-use-kafka.md",
+				"--- a/docs/adr/0002-use-kafka.md",
+				"+++ b/docs/adr/0002-use-kafka.md",
+				"@@ -1 +1 @@",
+				"-# 2. Use Kafka",
+				"+# 2. Use Kafka for events",
+				"@@ -7 +7 @@",
+				"-Accepted",
+				"+ Accepted",
+			),
+			want: nil,
+		},
+		{
+			name: "removal in one file does not pair with another",
+			log: gitLog(
+				"\x002026-01-05T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0001-a.md b/docs/adr/0001-a.md",
+				"--- a/docs/adr/0001-a.md",
+				"+++ /dev/null",
+				"@@ -7 +0,0 @@",
+				"-Accepted",
+				"diff --git a/docs/adr/0002-b.md b/docs/adr/0002-b.md",
+				"--- /dev/null",
+				"+++ b/docs/adr/0002-b.md",
+				"@@ -0,0 +7 @@",
+				"+Draft",
+			),
+			want: nil,
+		},
+		{
+			name: "changes before a rename use the new filename",
+			log: gitLog(
+				"\x002026-04-01T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0002-use-pulsar.md b/docs/adr/0002-use-pulsar.md",
+				"--- a/docs/adr/0002-use-pulsar.md",
+				"+++ b/docs/adr/0002-use-pulsar.md",
+				"@@ -7 +7 @@ Date: 2026-01-05",
+				"-Proposed",
+				"+Accepted",
+				"\x002026-03-01T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0002-use-kafka.md b/docs/adr/0002-use-pulsar.md",
+				"similarity index 91%",
+				"rename from docs/adr/0002-use-kafka.md",
+				"rename to docs/adr/0002-use-pulsar.md",
+				"index ac8e2a6..ca3ae2b 100644",
+				"--- a/docs/adr/0002-use-kafka.md",
+				"+++ b/docs/adr/0002-use-pulsar.md",
+				"@@ -1 +1 @@",
+				"-# 2. Use Kafka",
+				"+# 2. Use Pulsar",
+				"\x002026-02-01T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0002-use-kafka.md b/docs/adr/0002-use-kafka.md",
+				"--- a/docs/adr/0002-use-kafka.md",
+				"+++ b/docs/adr/0002-use-kafka.md",
+				"@@ -7 +7 @@ Date: 2026-01-05",
+				"-Draft",
+				"+Proposed",
+			),
+			want: []statusChange{
+				{Date: day("2026-02-01").Add(10 * time.Hour), Filename: "0002-use-pulsar.md", From: adr.StatusDraft, To: adr.StatusProposed},
+				{Date: day("2026-04-01").Add(10 * time.Hour), Filename: "0002-use-pulsar.md", From: adr.StatusProposed, To: adr.StatusAccepted},
+			},
+		},
+		{
+			name: "chained renames and a reused filename",
+			log: gitLog(
+				"\x002026-05-01T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0004-a.md b/docs/adr/0004-a.md",
+				"--- a/docs/adr/0004-a.md",
+				"+++ b/docs/adr/0004-a.md",
+				"@@ -7 +7 @@",
+				"-Draft",
+				"+Proposed",
+				"\x002026-04-01T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0003-b.md b/docs/adr/0005-c.md",
+				"rename from docs/adr/0003-b.md",
+				"rename to docs/adr/0005-c.md",
+				"\x002026-03-01T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0004-a.md b/docs/adr/0003-b.md",
+				"rename from docs/adr/0004-a.md",
+				"rename to docs/adr/0003-b.md",
+				"\x002026-02-01T10:00:00+00:00",
+				"",
+				"diff --git a/docs/adr/0004-a.md b/docs/adr/0004-a.md",
+				"--- a/docs/adr/0004-a.md",
+				"+++ b/docs/adr/0004-a.md",
+				"@@ -7 +7 @@",
+				"-Accepted",
+				"+Deprecated",
+			),
+			want: []statusChange{
+				{Date: day("2026-02-01").Add(10 * time.Hour), Filename: "0005-c.md", From: adr.StatusAccepted, To: adr.StatusDeprecated},
+				{Date: day("2026-05-01").Add(10 * time.Hour), Filename: "0004-a.md", From: adr.StatusDraft, To: adr.StatusProposed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStatusLog(tt.log)
+			if err != nil {
+				t.Fatalf("parseStatusLog() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseStatusLog() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Date.Equal(tt.want[i].Date) || got[i].Filename != tt.want[i].Filename ||
+					got[i].From != tt.want[i].From || got[i].To != tt.want[i].To {
+					t.Errorf("change %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	adrs := []*adr.ADR{
+		{Number: 1, Title: "One", Date: day("2026-01-10"), Status: adr.StatusAccepted, Filename: "0001-one.md"},
+		{Number: 2, Title: "Two", Date: day("2026-01-10"), Status: adr.StatusAccepted, Filename: "0002-two.md",
+			History: []adr.StatusChange{
+				{Date: day("2026-01-10"), To: adr.StatusDraft},
+				{Date: day("2026-02-01"), From: adr.StatusDraft, To: adr.StatusAccepted},
+			}},
+		{Number: 3, Title: "Three", Date: day("2026-03-01"), Status: adr.StatusProposed, Filename: "0003-three.md"},
+	}
+	changes := []statusChange{
+		{Date: day("2026-01-20"), Filename: "0001-one.md", From: adr.StatusProposed, To: adr.StatusAccepted},
+		{Date: day("2026-01-25"), Filename: "0002-two.md", From: adr.StatusDraft, To: adr.StatusProposed},
+		{Date: day("2026-01-26"), Filename: "0009-gone.md", From: adr.StatusDraft, To: adr.StatusProposed},
+	}
+
+	// describe renders a row as "date number" plus the new status of a change
+	describe := func(rows []timelineRow) []string {
+		var out []string
+		for _, r := range rows {
+			s := r.Date.Format("2006-01-02") + " " + formatNumber(r.ADR.Number)
+			if r.Change != nil {
+				s += " -> " + string(r.Change.To)
+			}
+			out = append(out, s)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		since time.Time
+		want  []string
+	}{
+		{
+			name: "all rows",
+			want: []string{
+				"2026-01-10 0001",
+				"2026-01-10 0002",
+				"2026-01-10 0002 -> Draft",
+				"2026-01-20 0001 -> Accepted",
+				"2026-02-01 0002 -> Accepted",
+				"2026-03-01 0003",
+			},
+		},
+		{
+			name:  "since",
+			since: day("2026-01-20"),
+			want: []string{
+				"2026-01-20 0001 -> Accepted",
+				"2026-02-01 0002 -> Accepted",
+				"2026-03-01 0003",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := describe(buildTimeline(adrs, changes, tt.since))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("buildTimeline() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestSupersessionLanes(t *testing.T) {
+	if err := ui.SetColor("never"); err != nil {
+		t.Fatal(err)
+	}
+
+	supersedes := func(n int) []string {
+		return []string{"Supersedes [ADR-" + formatNumber(n) + "](" + formatNumber(n) + "-x.md)"}
+	}
+
+	tests := []struct {
+		name  string
+		adrs  []*adr.ADR
+		lines []int
+		want  []string
+	}{
+		{
+			name:  "no supersessions",
+			adrs:  []*adr.ADR{{Number: 1}, {Number: 2}},
+			lines: []int{-1, 0, 1},
+			want:  []string{"", "", ""},
+		},
+		{
+			name:  "adjacent",
+			adrs:  []*adr.ADR{{Number: 1}, {Number: 2, StatusExtra: supersedes(1)}},
+			lines: []int{0, 1},
+			want:  []string{"+-> ", "+-- "},
+		},
+		{
+			name:  "across a heading",
+			adrs:  []*adr.ADR{{Number: 1}, {Number: 2, StatusExtra: supersedes(1)}},
+			lines: []int{-1, 0, -1, 1},
+			want:  []string{"    ", "+-> ", "|   ", "+-- "},
+		},
+		{
+			name: "overlapping arcs use separate lanes",
+			adrs: []*adr.ADR{
+				{Number: 1},
+				{Number: 2},
+				{Number: 3, StatusExtra: supersedes(1)},
+				{Number: 4, StatusExtra: supersedes(2)},
+			},
+			lines: []int{0, 1, 2, 3},
+			want:  []string{"+---> ", "| +-> ", "+-+-- ", "  +-- "},
+		},
+		{
+			name: "disjoint arcs share a lane",
+			adrs: []*adr.ADR{
+				{Number: 1},
+				{Number: 2, StatusExtra: supersedes(1)},
+				{Number: 3},
+				{Number: 4, StatusExtra: supersedes(3)},
+			},
+			lines: []int{0, 1, 2, 3},
+			want:  []string{"+-> ", "+-- ", "+-> ", "+-- "},
+		},
+		{
+			name:  "superseded ADR not shown",
+			adrs:  []*adr.ADR{{Number: 2, StatusExtra: supersedes(1)}},
+			lines: []int{0},
+			want:  []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := make([]timelineRow, len(tt.adrs))
+			for i, a := range tt.adrs {
+				rows[i] = timelineRow{ADR: a}
+			}
+			var got []string
+			for _, g := range supersessionLanes(rows, tt.lines) {
+				got = append(got, ui.Plain(g))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("supersessionLanes() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -32,7 +32,7 @@ var updateCmd = &cobra.Command{
 	Short: "Update stamp to the latest version",
 	Long:  `Check for updates and download the latest version of stamp from GitHub releases.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println(ui.Muted("Checking for updates..."))
+		fmt.Fprintln(ui.Stdout, ui.Muted("Checking for updates..."))
 
 		release, err := getLatestRelease()
 		if err != nil {
@@ -43,12 +43,12 @@ var updateCmd = &cobra.Command{
 		currentVersion := strings.TrimPrefix(Version, "v")
 
 		if latestVersion == currentVersion {
-			fmt.Println(ui.Success(fmt.Sprintf("Already up to date (%s)", Version)))
+			fmt.Fprintln(ui.Stdout, ui.Success(fmt.Sprintf("Already up to date (%s)", Version)))
 			return nil
 		}
 
 		if Version == "dev" {
-			fmt.Println(ui.Warning("Running development build, skipping update"))
+			fmt.Fprintln(ui.Stdout, ui.Warning("Running development build, skipping update"))
 			return nil
 		}
 
@@ -65,13 +65,13 @@ var updateCmd = &cobra.Command{
 			return fmt.Errorf("no binary found for %s/%s", runtime.GOOS, runtime.GOARCH)
 		}
 
-		fmt.Printf("Updating %s -> %s\n", ui.Muted(Version), ui.Bold(release.TagName))
+		fmt.Fprintf(ui.Stdout, "Updating %s -> %s\n", ui.Muted(Version), ui.Bold(release.TagName))
 
 		if err := doUpdate(downloadURL); err != nil {
 			return fmt.Errorf("failed to apply update: %w", err)
 		}
 
-		fmt.Println(ui.Success(fmt.Sprintf("Updated to %s", release.TagName)))
+		fmt.Fprintln(ui.Stdout, ui.Success(fmt.Sprintf("Updated to %s", release.TagName)))
 		return nil
 	},
 }
@@ -2,16 +2,39 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/stef16robbe/stamp/internal/ui"
 )
 
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version number",
-	Long:  `Print the version number of stamp.`,
+	Long: `Print the version number of stamp.
+
+With the check_updates setting enabled, also reports whether a newer release
+is available.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("stamp %s\n", Version)
+		fmt.Fprintf(ui.Stdout, "stamp %s\n", Version)
+
+		cfg, err := effectiveConfig()
+		if err != nil {
+			return
+		}
+		s := cfg.Settings
+		if s.CheckUpdates == nil || !*s.CheckUpdates || Version == "dev" {
+			return
+		}
+
+		release, err := getLatestRelease()
+		if err != nil {
+			fmt.Fprintln(ui.Stdout, ui.Muted("Could not check for updates: "+err.Error()))
+			return
+		}
+		if strings.TrimPrefix(release.TagName, "v") != strings.TrimPrefix(Version, "v") {
+			fmt.Fprintln(ui.Stdout, ui.Warning(fmt.Sprintf("stamp %s is available, run 'stamp update' to install it", release.TagName)))
+		}
 	},
 }
 
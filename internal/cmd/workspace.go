@@ -0,0 +1,473 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"charm.land/lipgloss/v2/table"
+	"github.com/spf13/cobra"
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/diagram"
+	"github.com/stef16robbe/stamp/internal/ui"
+	"github.com/stef16robbe/stamp/internal/workspace"
+)
+
+var (
+	workspaceFile        string
+	workspaceName        string
+	workspaceGraphFormat string
+	workspaceOut         string
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Work with the ADRs of several projects at once",
+	Long: `Aggregates the ADRs of several local stamp projects, e.g. the checkouts of
+an organization's service repositories. ADR numbers are qualified with their
+project name, as in "payments:0003".
+
+The projects are listed in workspace.yaml in the user config directory
+(~/.config/stamp/workspace.yaml on Linux); use --file to read another file.
+
+Examples:
+  stamp workspace add ~/src/payments
+  stamp workspace list
+  stamp workspace search kafka
+  stamp workspace graph --format svg --out decisions.svg
+  stamp workspace export --out decisions.html`,
+}
+
+// loadWorkspace reads the workspace file and returns it with its path
+func loadWorkspace() (*workspace.Workspace, string, error) {
+	path := workspaceFile
+	if path == "" {
+		var err error
+		path, err = workspace.DefaultPath()
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	w, err := workspace.Load(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return w, path, nil
+}
+
+// collectWorkspace loads the ADRs of every project in the workspace,
+// printing a warning for each project or file that could not be read
+func collectWorkspace() ([]*workspace.Entry, error) {
+	w, path, err := loadWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	if len(w.Projects) == 0 {
+		return nil, fmt.Errorf("no projects in %s (add one with 'stamp workspace add <path>')", path)
+	}
+
+	entries, warnings := w.Collect()
+	for _, warning := range warnings {
+		fmt.Fprintln(ui.Stderr, ui.Warning("Skipped "+warning.Error()))
+	}
+	return entries, nil
+}
+
+var workspaceAddCmd = &cobra.Command{
+	Use:   "add <path>",
+	Short: "Add a project to the workspace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		w, path, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		p, err := w.Add(args[0], workspaceName)
+		if err != nil {
+			return err
+		}
+
+		if err := w.Save(path); err != nil {
+			return fmt.Errorf("failed to save workspace: %w", err)
+		}
+
+		fmt.Fprintln(ui.Stdout, ui.Success("Added "+ui.Bold(p.Name)+" "+ui.Muted(p.Path)))
+		return nil
+	},
+}
+
+var workspaceRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a project from the workspace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		w, path, err := loadWorkspace()
+		if err != nil {
+			return err
+		}
+
+		if err := w.Remove(args[0]); err != nil {
+			return err
+		}
+
+		if err := w.Save(path); err != nil {
+			return fmt.Errorf("failed to save workspace: %w", err)
+		}
+
+		fmt.Fprintln(ui.Stdout, ui.Success("Removed "+ui.Bold(args[0])))
+		return nil
+	},
+}
+
+var workspaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the ADRs of every project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := collectWorkspace()
+		if err != nil {
+			return err
+		}
+
+		if len(entries) == 0 {
+			fmt.Fprintln(ui.Stdout, ui.Warning("No ADRs found in the workspace"))
+			return nil
+		}
+
+		fmt.Fprintln(ui.Stdout, workspaceTable(entries))
+		return nil
+	},
+}
+
+var workspaceSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the ADRs of every project",
+	Long: `Lists the ADRs whose title, context, decision or consequences contain every
+word of the query, ignoring case.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := collectWorkspace()
+		if err != nil {
+			return err
+		}
+
+		query := strings.Join(args, " ")
+		matches := workspace.Search(entries, query)
+		if len(matches) == 0 {
+			fmt.Fprintln(ui.Stdout, ui.Warning("No ADRs match "+query))
+			return nil
+		}
+
+		fmt.Fprintln(ui.Stdout, workspaceTable(matches))
+		return nil
+	},
+}
+
+// workspaceTable renders entries in the style of 'stamp list', with the
+// project as an extra column
+func workspaceTable(entries []*workspace.Entry) *table.Table {
+	rows := make([][]string, len(entries))
+	for i, e := range entries {
+		rows[i] = []string{
+			e.Qualifier(),
+			e.Scheme.FormatNumber(e.ADR.Number),
+			e.ADR.Title,
+			ui.RenderStatus(e.ADR.Status),
+			e.ADR.Date.Format("2006-01-02"),
+		}
+	}
+
+	return listTable([]string{"PROJECT", "NUM", "TITLE", "STATUS", "DATE"}, rows)
+}
+
+var workspaceGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Generate a relationship graph of every project",
+	Long: `Generates a graph of the ADRs of every project, grouped by project. Links
+between collections of the same project are included.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := collectWorkspace()
+		if err != nil {
+			return err
+		}
+
+		edges := workspace.Edges(entries)
+
+		var output string
+		switch workspaceGraphFormat {
+		case "mermaid":
+			output = generateWorkspaceMermaid(entries, edges)
+		case "dot":
+			output = generateWorkspaceDot(entries, edges)
+		case "svg":
+			output = buildWorkspaceDiagram(entries, edges).SVG()
+		default:
+			return fmt.Errorf("invalid format: %s (valid: mermaid, dot, svg)", workspaceGraphFormat)
+		}
+
+		return writeOutput(output, workspaceOut, false)
+	},
+}
+
+var workspaceExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the ADRs of every project as a single HTML page",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := collectWorkspace()
+		if err != nil {
+			return err
+		}
+
+		output, err := generateWorkspaceHTML(entries)
+		if err != nil {
+			return fmt.Errorf("failed to render HTML: %w", err)
+		}
+
+		return writeOutput(output, workspaceOut, false)
+	},
+}
+
+// workspaceNodeIDs assigns graph node IDs to entries, which may share ADR
+// numbers across projects
+func workspaceNodeIDs(entries []*workspace.Entry) map[*workspace.Entry]int {
+	ids := make(map[*workspace.Entry]int, len(entries))
+	for i, e := range entries {
+		ids[e] = i + 1
+	}
+	return ids
+}
+
+// workspaceLabel is the node label of an entry: its qualified ID and title
+func workspaceLabel(e *workspace.Entry) string {
+	return e.Qualifier() + ":" + nodeLabel(e.Scheme, e.ADR)
+}
+
+// groupByQualifier returns the qualifiers of entries in order of appearance,
+// with the entries of each
+func groupByQualifier(entries []*workspace.Entry) ([]string, map[string][]*workspace.Entry) {
+	var order []string
+	groups := make(map[string][]*workspace.Entry)
+	for _, e := range entries {
+		q := e.Qualifier()
+		if _, ok := groups[q]; !ok {
+			order = append(order, q)
+		}
+		groups[q] = append(groups[q], e)
+	}
+	return order, groups
+}
+
+func generateWorkspaceMermaid(entries []*workspace.Entry, edges []workspace.Edge) string {
+	var sb strings.Builder
+	ids := workspaceNodeIDs(entries)
+
+	sb.WriteString("graph TD\n")
+	for _, status := range adr.ValidStatuses {
+		color := colorFor(status)
+		fmt.Fprintf(&sb, "    classDef %s fill:%s,stroke:%s,color:%s\n", strings.ToLower(string(status)), color.Fill, color.Stroke, color.Text)
+	}
+	sb.WriteString("\n")
+
+	order, groups := groupByQualifier(entries)
+	for i, q := range order {
+		fmt.Fprintf(&sb, "    subgraph P%d[\"%s\"]\n", i+1, q)
+		for _, e := range groups[q] {
+			fmt.Fprintf(&sb, "        N%d[\"%s\"]%s\n", ids[e], nodeLabel(e.Scheme, e.ADR), statusToStyle[e.ADR.Status])
+		}
+		sb.WriteString("    end\n")
+	}
+	sb.WriteString("\n")
+
+	for _, edge := range edges {
+		arrow := relationToArrow[edge.Relation]
+		if arrow == "" {
+			arrow = "-->"
+		}
+		fmt.Fprintf(&sb, "    N%d %s N%d\n", ids[edge.Source], arrow, ids[edge.Target])
+	}
+
+	return sb.String()
+}
+
+func generateWorkspaceDot(entries []*workspace.Entry, edges []workspace.Edge) string {
+	var sb strings.Builder
+	ids := workspaceNodeIDs(entries)
+
+	sb.WriteString("digraph ADRs {\n")
+	sb.WriteString("    rankdir=TB;\n")
+	sb.WriteString("    node [shape=box, style=rounded];\n")
+	sb.WriteString("\n")
+
+	order, groups := groupByQualifier(entries)
+	for i, q := range order {
+		fmt.Fprintf(&sb, "    subgraph cluster_%d {\n", i+1)
+		fmt.Fprintf(&sb, "        label=\"%s\";\n", q)
+		for _, e := range groups[q] {
+			color := colorFor(e.ADR.Status)
+			fmt.Fprintf(&sb, "        N%d [label=\"%s\", fillcolor=\"%s\", style=\"filled,rounded\", fontcolor=\"%s\"];\n",
+				ids[e], nodeLabel(e.Scheme, e.ADR), color.Fill, color.Text)
+		}
+		sb.WriteString("    }\n")
+	}
+	sb.WriteString("\n")
+
+	for _, edge := range edges {
+		style := "solid"
+		if isDashed(edge.Relation) {
+			style = "dashed"
+		}
+		fmt.Fprintf(&sb, "    N%d -> N%d [label=\"%s\", style=%s];\n",
+			ids[edge.Source], ids[edge.Target], strings.ToLower(edge.Relation), style)
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func buildWorkspaceDiagram(entries []*workspace.Entry, edges []workspace.Edge) *diagram.Diagram {
+	ids := workspaceNodeIDs(entries)
+
+	nodes := make([]diagram.NodeSpec, len(entries))
+	for i, e := range entries {
+		color := colorFor(e.ADR.Status)
+		nodes[i] = diagram.NodeSpec{ID: ids[e], Label: workspaceLabel(e), Fill: color.Fill, Text: color.Text, Stroke: color.Stroke}
+	}
+
+	specs := make([]diagram.EdgeSpec, len(edges))
+	for i, edge := range edges {
+		specs[i] = diagram.EdgeSpec{
+			From:   ids[edge.Source],
+			To:     ids[edge.Target],
+			Label:  strings.ToLower(edge.Relation),
+			Dashed: isDashed(edge.Relation),
+		}
+	}
+
+	return diagram.Build(nodes, specs)
+}
+
+// htmlADR is the data of an ADR in the HTML export
+type htmlADR struct {
+	ID           string
+	Anchor       string
+	Title        string
+	Status       string
+	Color        string
+	TextColor    string
+	Date         string
+	Links        []string
+	Context      []string
+	Decision     []string
+	Consequences []string
+}
+
+// htmlProject is the data of a project in the HTML export
+type htmlProject struct {
+	Name string
+	ADRs []htmlADR
+}
+
+// paragraphs splits markdown text into paragraphs at blank lines
+func paragraphs(text string) []string {
+	var result []string
+	for _, p := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+var workspaceHTML = template.Must(template.New("workspace").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Architecture Decision Records</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; color: #1f2937; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; }
+th, td { text-align: left; padding: 0.3rem 0.6rem; border-bottom: 1px solid #e5e7eb; }
+.badge { border-radius: 0.25rem; padding: 0.05rem 0.4rem; font-size: 0.85em; }
+article { border-top: 1px solid #e5e7eb; padding-top: 1rem; margin-top: 1rem; }
+.meta { color: #6b7280; }
+p { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Architecture Decision Records</h1>
+<table>
+<tr><th>ID</th><th>Title</th><th>Status</th><th>Date</th></tr>
+{{- range .}}{{range .ADRs}}
+<tr><td><a href="#{{.Anchor}}">{{.ID}}</a></td><td>{{.Title}}</td><td><span class="badge" style="background: {{.Color}}; color: {{.TextColor}}">{{.Status}}</span></td><td>{{.Date}}</td></tr>
+{{- end}}{{end}}
+</table>
+{{- range .}}
+<h2>{{.Name}}</h2>
+{{- range .ADRs}}
+<article id="{{.Anchor}}">
+<h3>{{.ID}} {{.Title}}</h3>
+<p class="meta"><span class="badge" style="background: {{.Color}}; color: {{.TextColor}}">{{.Status}}</span> {{.Date}}</p>
+{{- range .Links}}
+<p class="meta">{{.}}</p>
+{{- end}}
+<h4>Context</h4>
+{{- range .Context}}
+<p>{{.}}</p>
+{{- end}}
+<h4>Decision</h4>
+{{- range .Decision}}
+<p>{{.}}</p>
+{{- end}}
+<h4>Consequences</h4>
+{{- range .Consequences}}
+<p>{{.}}</p>
+{{- end}}
+</article>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+func generateWorkspaceHTML(entries []*workspace.Entry) (string, error) {
+	order, groups := groupByQualifier(entries)
+
+	projects := make([]htmlProject, len(order))
+	for i, q := range order {
+		projects[i].Name = q
+		for _, e := range groups[q] {
+			a := e.ADR
+			projects[i].ADRs = append(projects[i].ADRs, htmlADR{
+				ID:           e.ID(),
+				Anchor:       strings.NewReplacer(":", "-", "/", "-").Replace(e.ID()),
+				Title:        a.Title,
+				Status:       string(a.Status),
+				Color:        colorFor(a.Status).Fill,
+				TextColor:    colorFor(a.Status).Text,
+				Date:         a.Date.Format("2006-01-02"),
+				Links:        a.StatusExtra,
+				Context:      paragraphs(a.Context),
+				Decision:     paragraphs(a.Decision),
+				Consequences: paragraphs(a.Consequences),
+			})
+		}
+	}
+
+	var sb strings.Builder
+	if err := workspaceHTML.Execute(&sb, projects); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func init() {
+	workspaceCmd.PersistentFlags().StringVar(&workspaceFile, "file", "", "Workspace file to use instead of the one in the user config directory")
+	workspaceAddCmd.Flags().StringVar(&workspaceName, "name", "", "Project name (default: the directory name)")
+	workspaceGraphCmd.Flags().StringVarP(&workspaceGraphFormat, "format", "f", "mermaid", "Output format: mermaid, dot or svg")
+	workspaceGraphCmd.Flags().StringVarP(&workspaceOut, "out", "o", "", "Write the graph to a file instead of stdout")
+	workspaceExportCmd.Flags().StringVarP(&workspaceOut, "out", "o", "", "Write the page to a file instead of stdout")
+
+	workspaceCmd.AddCommand(workspaceAddCmd, workspaceRemoveCmd, workspaceListCmd, workspaceSearchCmd, workspaceGraphCmd, workspaceExportCmd)
+	rootCmd.AddCommand(workspaceCmd)
+}
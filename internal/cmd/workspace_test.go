@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/config"
+	"github.com/stef16robbe/stamp/internal/workspace"
+)
+
+func TestWorkspaceGraphUsesEntrySchemes(t *testing.T) {
+	// The active collection must not decide how other logs are numbered
+	saved := active
+	active = &config.ResolvedCollection{Prefix: config.DefaultPrefix, Scheme: adr.DefaultScheme}
+	t.Cleanup(func() { active = saved })
+
+	entries := []*workspace.Entry{
+		{Project: "shop", Collection: "payments", Scheme: adr.Scheme{Width: 5, Prefix: "PAY"}, ADR: adr.NewADR(1, "Use Kafka")},
+		{Project: "shop", Collection: "platform", Scheme: adr.Scheme{Width: 3, Prefix: "PLAT"}, ADR: adr.NewADR(1, "Use Kubernetes")},
+	}
+
+	tests := []struct {
+		name string
+		got  string
+	}{
+		{"mermaid", generateWorkspaceMermaid(entries, nil)},
+		{"dot", generateWorkspaceDot(entries, nil)},
+		{"label", workspaceLabel(entries[0]) + "\n" + workspaceLabel(entries[1])},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, want := range []string{"00001: Use Kafka", "001: Use Kubernetes"} {
+				if !strings.Contains(tt.got, want) {
+					t.Errorf("output missing %q:\n%s", want, tt.got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/stef16robbe/stamp/internal/adr"
+)
+
+// DefaultPrefix is the ID prefix of ADRs in links, as in "[ADR-0001](...)"
+const DefaultPrefix = "ADR"
+
+// prefixRegex matches valid ID prefixes
+var prefixRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// IDs configures how ADRs are identified and named. Every field is optional;
+// unset fields fall back to the top-level settings, then to the defaults.
+type IDs struct {
+	// Prefix is used in links and graph node IDs, e.g. "SEC" for "SEC-0001"
+	Prefix string `yaml:"prefix,omitempty"`
+	// Width is the minimum number of digits of an ADR number
+	Width int `yaml:"width,omitempty"`
+	// Filename is the filename template, e.g. "{number}-{slug}.md"
+	Filename string `yaml:"filename,omitempty"`
+	// Numbering is "sequential" or "date"
+	Numbering string `yaml:"numbering,omitempty"`
+	// Heading is the title line template, e.g. "{id}. {title}"
+	Heading string `yaml:"heading,omitempty"`
+}
+
+// merge returns ids with its unset fields taken from fallback
+func (ids IDs) merge(fallback IDs) IDs {
+	if ids.Prefix == "" {
+		ids.Prefix = fallback.Prefix
+	}
+	if ids.Width == 0 {
+		ids.Width = fallback.Width
+	}
+	if ids.Filename == "" {
+		ids.Filename = fallback.Filename
+	}
+	if ids.Numbering == "" {
+		ids.Numbering = fallback.Numbering
+	}
+	if ids.Heading == "" {
+		ids.Heading = fallback.Heading
+	}
+	return ids
+}
+
+// validate checks the prefix and ID scheme
+func (ids IDs) validate() error {
+	if ids.Prefix != "" && !prefixRegex.MatchString(ids.Prefix) {
+		return fmt.Errorf("invalid prefix %q (expected letters and digits, starting with a letter)", ids.Prefix)
+	}
+	return ids.scheme().Validate()
+}
+
+// scheme returns the numbering and naming scheme of ids
+func (ids IDs) scheme() adr.Scheme {
+	return adr.Scheme{Width: ids.Width, Filename: ids.Filename, Numbering: ids.Numbering, Prefix: ids.Prefix, Heading: ids.Heading}
+}
+
+// Collection is a named ADR log with its own directory and numbering, used
+// to keep several logs in one repository (e.g. one per service)
+type Collection struct {
+	Directory string `yaml:"directory"`
+	IDs       `yaml:",inline"`
+}
+
+// ResolvedCollection is the collection a command works on, with its
+// directory made absolute
+type ResolvedCollection struct {
+	Name      string // empty when the configuration has a single directory
+	Directory string
+	Prefix    string
+	Scheme    adr.Scheme
+}
+
+// Store returns the ADR store of the collection
+func (r *ResolvedCollection) Store() *adr.Store {
+	store := adr.NewStore(r.Directory)
+	store.Scheme = r.Scheme
+	return store
+}
+
+// FormatID formats the ID of an ADR of the collection, like "ADR-0001"
+func (r *ResolvedCollection) FormatID(number int) string {
+	return r.Scheme.ID(number)
+}
+
+// newResolved resolves the ID settings of a collection in directory dir
+func newResolved(name, dir string, ids IDs) *ResolvedCollection {
+	ids = ids.merge(IDs{Prefix: DefaultPrefix})
+	return &ResolvedCollection{Name: name, Directory: dir, Prefix: ids.Prefix, Scheme: ids.scheme()}
+}
+
+// CollectionNames returns the names of the configured collections, sorted
+func (c *Config) CollectionNames() []string {
+	names := make([]string, 0, len(c.Collections))
+	for name := range c.Collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Root returns the project root, the directory containing the configuration
+// file
+func (c *Config) Root() (string, error) {
+	if c.root != "" {
+		return c.root, nil
+	}
+	configPath, err := FindConfigFile()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(configPath), nil
+}
+
+func (c *Config) resolve(root, name string) *ResolvedCollection {
+	col := c.Collections[name]
+	return newResolved(name, filepath.Join(root, col.Directory), col.IDs.merge(c.IDs))
+}
+
+// ResolveCollection returns the collection called name. If name is empty,
+// the collection is chosen from the current directory: a collection is
+// selected when the current directory is inside its ADR directory or that
+// directory's parent (e.g. anywhere in services/payments for
+// services/payments/adr), the deepest match winning. Otherwise the
+// collection named by the default setting is used.
+//
+// Configurations without collections resolve to their single directory.
+func (c *Config) ResolveCollection(name string) (*ResolvedCollection, error) {
+	root, err := c.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.Collections) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("unknown collection %q (no collections are configured in %s)", name, ConfigFileName)
+		}
+		return newResolved("", filepath.Join(root, c.Directory), c.IDs), nil
+	}
+
+	if name != "" {
+		if _, ok := c.Collections[name]; !ok {
+			return nil, fmt.Errorf("unknown collection %q (configured: %s)", name, strings.Join(c.CollectionNames(), ", "))
+		}
+		return c.resolve(root, name), nil
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		best, bestScore, ambiguous := "", 0, false
+		for _, n := range c.CollectionNames() {
+			score := ownership(wd, filepath.Join(root, c.Collections[n].Directory))
+			switch {
+			case score > bestScore:
+				best, bestScore, ambiguous = n, score, false
+			case score > 0 && score == bestScore:
+				ambiguous = true
+			}
+		}
+		if best != "" && !ambiguous {
+			return c.resolve(root, best), nil
+		}
+	}
+
+	if c.Default != "" {
+		if _, ok := c.Collections[c.Default]; !ok {
+			return nil, fmt.Errorf("default collection %q is not configured", c.Default)
+		}
+		return c.resolve(root, c.Default), nil
+	}
+
+	if len(c.Collections) == 1 {
+		return c.resolve(root, c.CollectionNames()[0]), nil
+	}
+
+	return nil, fmt.Errorf("several ADR collections are configured (%s); choose one with --collection",
+		strings.Join(c.CollectionNames(), ", "))
+}
+
+// ResolveCollections returns every collection, sorted by name
+func (c *Config) ResolveCollections() ([]*ResolvedCollection, error) {
+	if len(c.Collections) == 0 {
+		col, err := c.ResolveCollection("")
+		if err != nil {
+			return nil, err
+		}
+		return []*ResolvedCollection{col}, nil
+	}
+
+	root, err := c.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	var cols []*ResolvedCollection
+	for _, name := range c.CollectionNames() {
+		cols = append(cols, c.resolve(root, name))
+	}
+	return cols, nil
+}
+
+// ownership scores how closely the working directory wd belongs to the ADR
+// directory dir: the length of dir if wd is inside it, the length of its
+// parent if wd is inside that, and 0 otherwise
+func ownership(wd, dir string) int {
+	if within(wd, dir) {
+		return len(dir)
+	}
+	if parent := filepath.Dir(dir); within(wd, parent) {
+		return len(parent)
+	}
+	return 0
+}
+
+// within reports whether path is dir or inside it
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// validateCollections checks that every collection has a directory and a
+// valid ID scheme
+func (c *Config) validateCollections() error {
+	if err := c.IDs.validate(); err != nil {
+		return fmt.Errorf("%s: %w", ConfigFileName, err)
+	}
+	for _, name := range c.CollectionNames() {
+		col := c.Collections[name]
+		if col.Directory == "" {
+			return fmt.Errorf("collection %q has no directory in %s", name, ConfigFileName)
+		}
+		if err := col.IDs.merge(c.IDs).validate(); err != nil {
+			return fmt.Errorf("collection %q: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupMonorepo writes a config with two collections to a temp directory and
+// changes into it
+func setupMonorepo(t *testing.T, cfg *Config) string {
+	t.Helper()
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to resolve symlinks: %v", err)
+	}
+	for _, dir := range []string{"docs/adr", "services/payments/adr", "services/payments/src"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cfg.Save(root); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	return root
+}
+
+func monorepoConfig() *Config {
+	return &Config{
+		Collections: map[string]Collection{
+			"platform": {Directory: "docs/adr"},
+			"payments": {Directory: "services/payments/adr", IDs: IDs{Prefix: "PAY"}},
+		},
+		Default: "platform",
+	}
+}
+
+func TestResolveCollection(t *testing.T) {
+	root := setupMonorepo(t, monorepoConfig())
+
+	tests := []struct {
+		name       string
+		wd         string
+		collection string
+		want       string
+		wantPrefix string
+	}{
+		{"explicit", ".", "payments", "payments", "PAY"},
+		{"default at root", ".", "", "platform", DefaultPrefix},
+		{"inside ADR directory", "services/payments/adr", "", "payments", "PAY"},
+		{"inside service", "services/payments/src", "", "payments", "PAY"},
+		{"explicit overrides directory", "services/payments/src", "platform", "platform", DefaultPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.Chdir(filepath.Join(root, tt.wd)); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() error: %v", err)
+			}
+
+			col, err := cfg.ResolveCollection(tt.collection)
+			if err != nil {
+				t.Fatalf("ResolveCollection() error: %v", err)
+			}
+			if col.Name != tt.want {
+				t.Errorf("Name = %q, want %q", col.Name, tt.want)
+			}
+			if col.Prefix != tt.wantPrefix {
+				t.Errorf("Prefix = %q, want %q", col.Prefix, tt.wantPrefix)
+			}
+			wantDir := filepath.Join(root, cfg.Collections[tt.want].Directory)
+			if col.Directory != wantDir {
+				t.Errorf("Directory = %q, want %q", col.Directory, wantDir)
+			}
+		})
+	}
+}
+
+func TestResolveCollectionErrors(t *testing.T) {
+	cfg := monorepoConfig()
+	cfg.Default = ""
+	setupMonorepo(t, cfg)
+
+	if _, err := cfg.ResolveCollection("unknown"); err == nil || !strings.Contains(err.Error(), "payments, platform") {
+		t.Errorf("ResolveCollection(unknown) error = %v, want list of collections", err)
+	}
+
+	// Without a default, the project root belongs to no collection
+	if _, err := cfg.ResolveCollection(""); err == nil || !strings.Contains(err.Error(), "--collection") {
+		t.Errorf("ResolveCollection() error = %v, want hint to use --collection", err)
+	}
+}
+
+func TestResolveCollectionSingleDirectory(t *testing.T) {
+	root := setupMonorepo(t, &Config{Directory: "docs/adr"})
+	cfg := &Config{Directory: "docs/adr"}
+
+	col, err := cfg.ResolveCollection("")
+	if err != nil {
+		t.Fatalf("ResolveCollection() error: %v", err)
+	}
+	if col.Name != "" || col.Prefix != DefaultPrefix || col.Directory != filepath.Join(root, "docs/adr") {
+		t.Errorf("ResolveCollection() = %+v", col)
+	}
+
+	if _, err := cfg.ResolveCollection("payments"); err == nil {
+		t.Error("ResolveCollection(payments) expected error without collections")
+	}
+}
+
+func TestResolveCollections(t *testing.T) {
+	setupMonorepo(t, monorepoConfig())
+
+	cols, err := monorepoConfig().ResolveCollections()
+	if err != nil {
+		t.Fatalf("ResolveCollections() error: %v", err)
+	}
+	if len(cols) != 2 || cols[0].Name != "payments" || cols[1].Name != "platform" {
+		t.Errorf("ResolveCollections() = %+v, want payments and platform", cols)
+	}
+}
+
+func TestLoadCollectionWithoutDirectory(t *testing.T) {
+	setupMonorepo(t, &Config{Collections: map[string]Collection{"payments": {IDs: IDs{Prefix: "PAY"}}}})
+
+	if _, err := Load(); err == nil {
+		t.Error("Load() expected error for collection without directory")
+	}
+}
+
+func TestResolveCollectionIDs(t *testing.T) {
+	cfg := monorepoConfig()
+	cfg.IDs = IDs{Width: 3, Filename: "{date}-{number}-{slug}.md", Heading: "{id}. {title}"}
+	cfg.Collections["payments"] = Collection{
+		Directory: "services/payments/adr",
+		IDs:       IDs{Prefix: "PAY", Numbering: "date", Width: 10},
+	}
+	setupMonorepo(t, cfg)
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	platform, err := loaded.ResolveCollection("platform")
+	if err != nil {
+		t.Fatalf("ResolveCollection(platform) error: %v", err)
+	}
+	if platform.Prefix != DefaultPrefix || platform.Scheme.Width != 3 || platform.Scheme.Filename != cfg.Filename {
+		t.Errorf("platform = %+v, want top-level ID settings", platform)
+	}
+	if got := platform.FormatID(7); got != "ADR-007" {
+		t.Errorf("FormatID(7) = %q, want ADR-007", got)
+	}
+
+	payments, err := loaded.ResolveCollection("payments")
+	if err != nil {
+		t.Fatalf("ResolveCollection(payments) error: %v", err)
+	}
+	if payments.Prefix != "PAY" || payments.Scheme.Width != 10 || payments.Scheme.Numbering != "date" || payments.Scheme.Filename != cfg.Filename {
+		t.Errorf("payments = %+v, want collection settings over top-level ones", payments)
+	}
+	if got := payments.Scheme.FormatHeading(7, "Use Kafka"); got != "# PAY-0000000007. Use Kafka" {
+		t.Errorf("FormatHeading() = %q, want the collection's ID", got)
+	}
+}
+
+func TestLoadInvalidIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+	}{
+		{"filename without slug", &Config{Directory: "docs/adr", IDs: IDs{Filename: "{number}.md"}}},
+		{"invalid prefix", &Config{Directory: "docs/adr", IDs: IDs{Prefix: "A-B"}}},
+		{"heading without title", &Config{Directory: "docs/adr", IDs: IDs{Heading: "{id}"}}},
+		{"collection numbering", &Config{Collections: map[string]Collection{
+			"payments": {Directory: "services/payments/adr", IDs: IDs{Numbering: "ulid"}},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupMonorepo(t, tt.cfg)
+			if _, err := Load(); err == nil {
+				t.Error("Load() expected error")
+			}
+		})
+	}
+}
@@ -11,17 +11,79 @@ import (
 const ConfigFileName = ".stamp.yaml"
 
 type Config struct {
-	Directory string `yaml:"directory"`
+	// Version is the configuration format, see CurrentVersion
+	Version   int    `yaml:"version,omitempty"`
+	Directory string `yaml:"directory,omitempty"`
+	// Collections replaces Directory in repositories with several ADR logs
+	Collections map[string]Collection `yaml:"collections,omitempty"`
+	// Default is the collection used outside every collection's directory
+	Default string `yaml:"default,omitempty"`
+	// Review configures 'stamp review'
+	Review Review `yaml:"review,omitempty"`
+	// Stale configures 'stamp stale'
+	Stale Stale `yaml:"stale,omitempty"`
+	// IDs sets the numbering and naming of ADRs, which collections may override
+	IDs `yaml:",inline"`
+	// Settings are personal preferences layered over the user configuration
+	Settings `yaml:",inline"`
+
+	// root is the directory containing the configuration file, set by Load
+	root string
+}
+
+// Review configures the approval of proposed ADRs
+type Review struct {
+	// Quorum is the number of approvals that accepts an ADR; 0 requires every
+	// requested reviewer
+	Quorum int `yaml:"quorum,omitempty"`
+}
+
+// DefaultStaleDays is how long a draft or proposed ADR may go without
+// activity unless stale.days says otherwise
+const DefaultStaleDays = 30
+
+// Stale configures the reminders of ADRs to revisit
+type Stale struct {
+	// Days is how long a draft or proposed ADR may go without activity; 0
+	// means DefaultStaleDays
+	Days int `yaml:"days,omitempty"`
 }
 
 func DefaultConfig() *Config {
 	return &Config{
+		Version:   CurrentVersion,
 		Directory: "docs/adr",
 	}
 }
 
+// Load loads the configuration of the project containing the current
+// directory, or the file named by $STAMP_CONFIG
 func Load() (*Config, error) {
-	configPath, err := FindConfigFile()
+	if path := os.Getenv(EnvConfig); path != "" {
+		return LoadFile(path)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFrom(dir)
+}
+
+// LoadFrom loads the configuration of the project containing dir
+func LoadFrom(dir string) (*Config, error) {
+	configPath, err := findConfigFileFrom(dir)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(configPath)
+}
+
+// LoadFile loads a project configuration file. Its directory is the project
+// root. Settings are layered: defaults, the user configuration file, the
+// project file, then STAMP_* environment variables.
+func LoadFile(path string) (*Config, error) {
+	configPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, err
 	}
@@ -31,11 +93,35 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	// Older formats are read as if migrated; newer ones are refused
+	data, _, err = Upgrade(data, configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics, err := Validate(data, configPath, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(diagnostics) > 0 {
+		return nil, joinDiagnostics(diagnostics)
+	}
+
 	var cfg Config
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, err
 	}
 
+	if err := cfg.validateCollections(); err != nil {
+		return nil, err
+	}
+
+	cfg.Settings, err = layerSettings(cfg.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.root = filepath.Dir(configPath)
 	return &cfg, nil
 }
 
@@ -55,7 +141,11 @@ func FindConfigFile() (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return findConfigFileFrom(dir)
+}
 
+// findConfigFileFrom looks for the configuration file in dir and its parents
+func findConfigFileFrom(dir string) (string, error) {
 	for {
 		configPath := filepath.Join(dir, ConfigFileName)
 		if _, err := os.Stat(configPath); err == nil {
@@ -72,12 +162,12 @@ func FindConfigFile() (string, error) {
 	return "", errors.New("no .stamp.yaml found (run 'stamp init' first)")
 }
 
+// ADRDirectory returns the absolute ADR directory of the collection selected
+// by the current directory (see ResolveCollection)
 func (c *Config) ADRDirectory() (string, error) {
-	configPath, err := FindConfigFile()
+	col, err := c.ResolveCollection("")
 	if err != nil {
 		return "", err
 	}
-
-	projectRoot := filepath.Dir(configPath)
-	return filepath.Join(projectRoot, c.Directory), nil
+	return col.Directory, nil
 }
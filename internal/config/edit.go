@@ -0,0 +1,264 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// SetValue sets the dotted key to value in the configuration file content,
+// keeping the rest of the file, comments included, as it is
+func SetValue(data []byte, key string, value any) ([]byte, error) {
+	f, err := parser.ParseBytes(data, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(key, ".")
+
+	// snippet renders parts[i:] = value as YAML, indented by column
+	snippet := func(i, column int) (*ast.MappingNode, error) {
+		var v any = value
+		for j := len(parts) - 1; j >= i; j-- {
+			v = yaml.MapSlice{{Key: parts[j], Value: v}}
+		}
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		sf, err := parser.ParseBytes(out, 0)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := sf.Docs[0].Body.(*ast.MappingNode)
+		if !ok {
+			m = ast.Mapping(sf.Docs[0].Body.GetToken(), false, sf.Docs[0].Body.(*ast.MappingValueNode))
+		}
+		m.AddColumn(column)
+		return m, nil
+	}
+
+	if len(f.Docs) == 0 || f.Docs[0].Body == nil {
+		m, err := snippet(0, 0)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(m.String() + "\n"), nil
+	}
+
+	doc := f.Docs[0]
+	mapping, err := asMapping(doc.Body)
+	if err != nil {
+		return nil, err
+	}
+	doc.Body = mapping
+
+	for i, part := range parts {
+		mv := lookupValue(mapping, part)
+		if mv == nil {
+			column := 0
+			if len(mapping.Values) > 0 {
+				column = mapping.Values[0].Key.GetToken().Position.Column - 1
+			}
+			m, err := snippet(i, column)
+			if err != nil {
+				return nil, err
+			}
+			mapping.Merge(m)
+			return render(f), nil
+		}
+
+		if i == len(parts)-1 {
+			m, err := snippet(i, 0)
+			if err != nil {
+				return nil, err
+			}
+			value := m.Values[0].Value
+			if comment := mv.Value.GetComment(); comment != nil {
+				if err := value.SetComment(comment); err != nil {
+					return nil, err
+				}
+			}
+			mv.Value = value
+			return render(f), nil
+		}
+
+		next, ok := mv.Value.(*ast.MappingNode)
+		if !ok {
+			if nv, single := mv.Value.(*ast.MappingValueNode); single {
+				next = ast.Mapping(nv.GetToken(), false, nv)
+				mv.Value = next
+			} else {
+				return nil, fmt.Errorf("%s is not a mapping", strings.Join(parts[:i+1], "."))
+			}
+		}
+		mapping = next
+	}
+	return nil, fmt.Errorf("empty key")
+}
+
+// UnsetValue removes the dotted key from the configuration file content. It
+// reports whether the key was set.
+func UnsetValue(data []byte, key string) ([]byte, bool, error) {
+	f, err := parser.ParseBytes(data, parser.ParseComments)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(f.Docs) == 0 || f.Docs[0].Body == nil {
+		return data, false, nil
+	}
+
+	mapping, err := asMapping(f.Docs[0].Body)
+	if err != nil {
+		return nil, false, err
+	}
+	f.Docs[0].Body = mapping
+
+	// Find the key, remembering the mappings on the way
+	type step struct {
+		mapping *ast.MappingNode
+		value   *ast.MappingValueNode
+	}
+	var steps []step
+	for i, part := range strings.Split(key, ".") {
+		if i > 0 {
+			next, err := asMapping(steps[i-1].value.Value)
+			if err != nil {
+				return data, false, nil
+			}
+			steps[i-1].value.Value = next
+			mapping = next
+		}
+		mv := lookupValue(mapping, part)
+		if mv == nil {
+			return data, false, nil
+		}
+		steps = append(steps, step{mapping, mv})
+	}
+
+	// Remove the key, and the mappings it leaves empty
+	for i := len(steps) - 1; i >= 0; i-- {
+		steps[i].mapping.Values = removeValue(steps[i].mapping.Values, steps[i].value)
+		if len(steps[i].mapping.Values) > 0 {
+			return render(f), true, nil
+		}
+	}
+	return nil, true, nil
+}
+
+// render prints a YAML node, ending it with a single newline
+func render(node fmt.Stringer) []byte {
+	return []byte(strings.TrimRight(node.String(), "\n") + "\n")
+}
+
+func asMapping(node ast.Node) (*ast.MappingNode, error) {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n, nil
+	case *ast.MappingValueNode:
+		return ast.Mapping(n.GetToken(), false, n), nil
+	}
+	return nil, fmt.Errorf("expected a mapping at line %d", node.GetToken().Position.Line)
+}
+
+func lookupValue(mapping *ast.MappingNode, name string) *ast.MappingValueNode {
+	for _, mv := range mapping.Values {
+		if mv.Key.GetToken().Value == name {
+			return mv
+		}
+	}
+	return nil
+}
+
+func removeValue(values []*ast.MappingValueNode, mv *ast.MappingValueNode) []*ast.MappingValueNode {
+	for i, v := range values {
+		if v == mv {
+			return append(values[:i], values[i+1:]...)
+		}
+	}
+	return values
+}
+
+// Value is a configuration key with its effective value
+type Value struct {
+	Key   string
+	Value string
+	IsSet bool // false when Value is the default
+}
+
+// Values returns every scalar key with its effective value, in schema order
+func (c *Config) Values() []Value {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return nil
+	}
+	var tree map[string]any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil
+	}
+
+	var values []Value
+	var walk func(key *Key, node map[string]any, prefix string)
+	walk = func(key *Key, node map[string]any, prefix string) {
+		for _, k := range key.keys {
+			name := prefix + k.Name
+			v, ok := node[k.Name]
+			switch k.Kind {
+			case kindObject:
+				child, _ := v.(map[string]any)
+				walk(k, child, name+".")
+			case kindMap:
+				entries, _ := v.(map[string]any)
+				names := make([]string, 0, len(entries))
+				for n := range entries {
+					names = append(names, n)
+				}
+				sort.Strings(names)
+				for _, n := range names {
+					child, _ := entries[n].(map[string]any)
+					walk(k, child, name+"."+n+".")
+				}
+			default:
+				if ok && v != nil {
+					values = append(values, Value{Key: name, Value: formatValue(v), IsSet: true})
+				} else if k.Default != "" && prefix == "" {
+					values = append(values, Value{Key: name, Value: k.Default})
+				}
+			}
+		}
+	}
+	walk(schema, tree, "")
+	return values
+}
+
+// Get returns the effective value of a dotted key
+func (c *Config) Get(key string) (Value, error) {
+	k, err := LookupKey(key)
+	if err != nil {
+		return Value{}, err
+	}
+	for _, v := range c.Values() {
+		if v.Key == key {
+			return v, nil
+		}
+	}
+	return Value{Key: key, Value: k.Default}, nil
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	}
+	return fmt.Sprint(v)
+}
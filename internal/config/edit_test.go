@@ -0,0 +1,88 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestSetValue(t *testing.T) {
+	input := "# Project settings\ndirectory: docs/adr # ADRs live here\n\ncollections:\n  payments:\n    directory: services/payments/adr\n"
+
+	tests := []struct {
+		name  string
+		key   string
+		value any
+		want  string
+	}{
+		{"replace", "directory", "decisions",
+			"# Project settings\ndirectory: decisions # ADRs live here\n\ncollections:\n  payments:\n    directory: services/payments/adr\n"},
+		{"add top-level", "width", 3,
+			input + "width: 3\n"},
+		{"add nested", "collections.payments.prefix", "PAY",
+			"# Project settings\ndirectory: docs/adr # ADRs live here\n\ncollections:\n  payments:\n    directory: services/payments/adr\n    prefix: PAY\n"},
+		{"add collection", "collections.billing.directory", "billing/adr",
+			"# Project settings\ndirectory: docs/adr # ADRs live here\n\ncollections:\n  payments:\n    directory: services/payments/adr\n  billing:\n    directory: billing/adr\n"},
+		{"quoted", "filename", "{date}-{number}-{slug}.md",
+			input + "filename: \"{date}-{number}-{slug}.md\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetValue([]byte(input), tt.key, tt.value)
+			if err != nil {
+				t.Fatalf("SetValue() error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("SetValue() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetValueEmptyFile(t *testing.T) {
+	got, err := SetValue(nil, "editor", "vim")
+	if err != nil {
+		t.Fatalf("SetValue() error: %v", err)
+	}
+	if string(got) != "editor: vim\n" {
+		t.Errorf("SetValue() = %q, want %q", got, "editor: vim\n")
+	}
+}
+
+func TestUnsetValue(t *testing.T) {
+	input := "directory: docs/adr\nprefix: SEC\ncollections:\n  payments:\n    directory: services/payments/adr\n"
+
+	got, found, err := UnsetValue([]byte(input), "prefix")
+	if err != nil || !found {
+		t.Fatalf("UnsetValue(prefix) = %v, %v", found, err)
+	}
+	if want := "directory: docs/adr\ncollections:\n  payments:\n    directory: services/payments/adr\n"; string(got) != want {
+		t.Errorf("UnsetValue(prefix) =\n%s\nwant\n%s", got, want)
+	}
+
+	// Mappings left empty are removed
+	got, found, err = UnsetValue([]byte(input), "collections.payments.directory")
+	if err != nil || !found {
+		t.Fatalf("UnsetValue(collections.payments.directory) = %v, %v", found, err)
+	}
+	if want := "directory: docs/adr\nprefix: SEC\n"; string(got) != want {
+		t.Errorf("UnsetValue(collections.payments.directory) =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, found, _ := UnsetValue([]byte(input), "width"); found {
+		t.Error("UnsetValue(width) found a key that isn't set")
+	}
+}
+
+func TestConfigValues(t *testing.T) {
+	cfg := &Config{Directory: "docs/adr", IDs: IDs{Prefix: "SEC"}}
+
+	v, err := cfg.Get("prefix")
+	if err != nil || v.Value != "SEC" || !v.IsSet {
+		t.Errorf("Get(prefix) = %+v, %v", v, err)
+	}
+
+	v, err = cfg.Get("width")
+	if err != nil || v.Value != "4" || v.IsSet {
+		t.Errorf("Get(width) = %+v, %v, want default", v, err)
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// CurrentVersion is the configuration format this version of stamp reads and
+// writes. Files without a version key are version 0.
+const CurrentVersion = 1
+
+// migration upgrades a configuration file to the next version by editing its
+// YAML in place, so comments survive
+type migration struct {
+	description string
+	apply       func(root *ast.MappingNode) error
+}
+
+// migrations[v] upgrades version v to v+1. Add one, and bump CurrentVersion,
+// whenever the format changes in a way older versions of stamp would misread.
+var migrations = []migration{
+	// Version 1 only introduced the version key
+	{description: "record the configuration version", apply: func(*ast.MappingNode) error { return nil }},
+}
+
+// VersionError reports a configuration file written for a newer stamp
+type VersionError struct {
+	File    string
+	Version int
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("%s: configuration version %d is newer than this stamp supports (up to %d); upgrade stamp to use it",
+		e.File, e.Version, CurrentVersion)
+}
+
+// Migration is the result of upgrading a configuration file
+type Migration struct {
+	From, To int
+	// Changes describes each migration applied, oldest first
+	Changes []string
+	Data    []byte
+}
+
+// Migrate upgrades the content of a project configuration file to
+// CurrentVersion, recording the new version in it
+func Migrate(data []byte, file string) (*Migration, error) {
+	f, mapping, from, err := parseVersioned(data, file)
+	if err != nil {
+		return nil, err
+	}
+	m := &Migration{From: from, To: CurrentVersion, Data: data}
+	if from == CurrentVersion {
+		return m, nil
+	}
+
+	if mapping != nil {
+		if err := upgrade(mapping, from); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		data = render(f)
+	}
+	for _, mg := range migrations[from:] {
+		m.Changes = append(m.Changes, mg.description)
+	}
+
+	if mapping != nil && lookupValue(mapping, "version") != nil {
+		m.Data, err = SetValue(data, "version", CurrentVersion)
+		if err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	m.Data = insertVersion(data)
+	return m, nil
+}
+
+// Upgrade returns the content of a project configuration file migrated in
+// memory to CurrentVersion, and the version the file was written in. Unlike
+// Migrate, it doesn't add the version key, so positions in diagnostics still
+// match the file.
+func Upgrade(data []byte, file string) ([]byte, int, error) {
+	f, mapping, from, err := parseVersioned(data, file)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from == CurrentVersion || mapping == nil {
+		return data, from, nil
+	}
+	if err := upgrade(mapping, from); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", file, err)
+	}
+	return render(f), from, nil
+}
+
+// upgrade applies the migrations after version from
+func upgrade(mapping *ast.MappingNode, from int) error {
+	for v := from; v < CurrentVersion; v++ {
+		if err := migrations[v].apply(mapping); err != nil {
+			return fmt.Errorf("migrating to version %d: %w", v+1, err)
+		}
+	}
+	return nil
+}
+
+// parseVersioned parses configuration content and reads its version. The
+// mapping is nil for an empty file.
+func parseVersioned(data []byte, file string) (*ast.File, *ast.MappingNode, int, error) {
+	f, err := parser.ParseBytes(data, parser.ParseComments)
+	if err != nil {
+		return nil, nil, 0, (&validator{file: file}).syntaxError(err)
+	}
+	if len(f.Docs) == 0 || f.Docs[0].Body == nil {
+		return f, nil, 0, nil
+	}
+
+	mapping, err := asMapping(f.Docs[0].Body)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("%s: %w", file, err)
+	}
+	f.Docs[0].Body = mapping
+
+	mv := lookupValue(mapping, "version")
+	if mv == nil {
+		return f, mapping, 0, nil
+	}
+	version, err := strconv.Atoi(mv.Value.GetToken().Value)
+	if err != nil || version < 0 {
+		pos := mv.Value.GetToken().Position
+		return nil, nil, 0, &Diagnostic{File: file, Line: pos.Line, Column: pos.Column,
+			Reason: fmt.Sprintf("invalid version %q", mv.Value.GetToken().Value)}
+	}
+	if version > CurrentVersion {
+		return nil, nil, 0, &VersionError{File: file, Version: version}
+	}
+	return f, mapping, version, nil
+}
+
+// insertVersion adds the version key at the top of configuration content,
+// after any leading comments
+func insertVersion(data []byte) []byte {
+	line := fmt.Sprintf("version: %d\n", CurrentVersion)
+	lines := strings.SplitAfter(string(data), "\n")
+	i := 0
+	for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "#") {
+		i++
+	}
+	return []byte(strings.Join(lines[:i], "") + line + strings.Join(lines[i:], ""))
+}
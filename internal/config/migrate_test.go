@@ -0,0 +1,104 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrationsMatchVersion(t *testing.T) {
+	if len(migrations) != CurrentVersion {
+		t.Errorf("%d migrations for version %d; add a migration when bumping CurrentVersion", len(migrations), CurrentVersion)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		changes int
+	}{
+		{"unversioned", "directory: docs/adr\n", "version: 1\ndirectory: docs/adr\n", 1},
+		{"after comments", "# ADR settings\ndirectory: docs/adr # here\n", "# ADR settings\nversion: 1\ndirectory: docs/adr # here\n", 1},
+		{"explicit version 0", "directory: docs/adr\nversion: 0\n", "directory: docs/adr\nversion: 1\n", 1},
+		{"empty", "", "version: 1\n", 1},
+		{"current", "version: 1\ndirectory: docs/adr\n", "version: 1\ndirectory: docs/adr\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Migrate([]byte(tt.input), ConfigFileName)
+			if err != nil {
+				t.Fatalf("Migrate() error: %v", err)
+			}
+			if string(m.Data) != tt.want {
+				t.Errorf("Migrate() =\n%s\nwant\n%s", m.Data, tt.want)
+			}
+			if len(m.Changes) != tt.changes {
+				t.Errorf("Migrate() applied %d changes, want %d", len(m.Changes), tt.changes)
+			}
+			if m.To != CurrentVersion {
+				t.Errorf("Migrate() To = %d, want %d", m.To, CurrentVersion)
+			}
+		})
+	}
+}
+
+func TestMigrateNewerVersion(t *testing.T) {
+	_, err := Migrate([]byte("version: 99\ndirectory: docs/adr\n"), ConfigFileName)
+	var verr *VersionError
+	if !errors.As(err, &verr) || verr.Version != 99 {
+		t.Fatalf("Migrate() error = %v, want VersionError", err)
+	}
+	if !strings.Contains(err.Error(), "upgrade stamp") {
+		t.Errorf("Migrate() error = %q, want an upgrade hint", err)
+	}
+
+	if _, err := Migrate([]byte("version: one\n"), ConfigFileName); err == nil || !strings.Contains(err.Error(), ":1:10: invalid version") {
+		t.Errorf("Migrate() error = %v, want invalid version with position", err)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	data, version, err := Upgrade([]byte("directory: docs/adr\n"), ConfigFileName)
+	if err != nil {
+		t.Fatalf("Upgrade() error: %v", err)
+	}
+	if version != 0 {
+		t.Errorf("Upgrade() version = %d, want 0", version)
+	}
+	// The version key isn't added, so positions stay the same
+	if string(data) != "directory: docs/adr\n" {
+		t.Errorf("Upgrade() = %q", data)
+	}
+}
+
+func TestLoadVersions(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	path := filepath.Join(dir, ConfigFileName)
+
+	if err := os.WriteFile(path, []byte("directory: decisions\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() unversioned error: %v", err)
+	}
+	if cfg.Directory != "decisions" {
+		t.Errorf("Directory = %q, want %q", cfg.Directory, "decisions")
+	}
+
+	// Keys of a newer format must not be reported as unknown
+	if err := os.WriteFile(path, []byte("version: 2\ndirectory: decisions\nstatuses: [open]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadFile(path)
+	var verr *VersionError
+	if !errors.As(err, &verr) {
+		t.Errorf("LoadFile() error = %v, want VersionError", err)
+	}
+}
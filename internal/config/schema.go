@@ -0,0 +1,490 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/stef16robbe/stamp/internal/adr"
+)
+
+var colorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// GraphFormats are the output formats of 'stamp graph'
+var GraphFormats = []string{"mermaid", "dot", "plantuml", "d2", "json", "graphml", "ascii", "svg", "png"}
+
+// Diagnostic describes a problem found in a configuration file
+type Diagnostic struct {
+	File   string
+	Line   int // 1-based, 0 when the problem isn't tied to a position
+	Column int
+	Reason string
+}
+
+func (d *Diagnostic) Error() string {
+	location := d.File
+	if location == "" {
+		location = "<input>"
+	}
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", location, d.Line, d.Column)
+	}
+	return location + ": " + d.Reason
+}
+
+// joinDiagnostics combines diagnostics into one error
+func joinDiagnostics(diagnostics []*Diagnostic) error {
+	errs := make([]error, len(diagnostics))
+	for i, d := range diagnostics {
+		errs[i] = d
+	}
+	return errors.Join(errs...)
+}
+
+// Kind is the type of a configuration value
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindInt
+	KindBool
+	kindObject // a fixed set of keys
+	kindMap    // any names, each with the same keys
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindInt:
+		return "integer"
+	case KindBool:
+		return "boolean"
+	case kindObject, kindMap:
+		return "mapping"
+	}
+	return "string"
+}
+
+// Key describes a configuration key
+type Key struct {
+	Name    string
+	Kind    Kind
+	Help    string
+	Default string // shown when the key is unset
+	// User keys may also be set in the user configuration file
+	User bool
+
+	check    func(value string) error
+	dir      bool // a directory relative to the project root
+	required bool
+	keys     []*Key // for kindObject, and the entries of kindMap
+}
+
+// idKeys are the ID settings, allowed at the top level and per collection
+func idKeys() []*Key {
+	return []*Key{
+		{Name: "prefix", Help: "ID prefix used in links and graphs", Default: DefaultPrefix, check: checkPrefix},
+		{Name: "width", Kind: KindInt, Help: "minimum number of digits of ADR numbers",
+			Default: strconv.Itoa(adr.DefaultScheme.Width), check: checkScheme(func(s *adr.Scheme, v string) { s.Width, _ = strconv.Atoi(v) })},
+		{Name: "filename", Help: "filename template with {number}, {slug} and {date}",
+			Default: adr.DefaultScheme.Filename, check: checkScheme(func(s *adr.Scheme, v string) { s.Filename = v })},
+		{Name: "numbering", Help: "sequential or date", Default: adr.DefaultScheme.Numbering,
+			check: checkScheme(func(s *adr.Scheme, v string) { s.Numbering = v })},
+		{Name: "heading", Help: "title line template with {title} and {number} or {id}", Default: adr.DefaultScheme.Heading,
+			check: checkScheme(func(s *adr.Scheme, v string) { s.Heading = v })},
+	}
+}
+
+// schema describes every key of .stamp.yaml
+var schema = &Key{Kind: kindObject, keys: append([]*Key{
+	{Name: "version", Kind: KindInt, Help: "configuration format, upgraded by 'stamp config migrate'", check: checkVersion},
+	{Name: "directory", Help: "ADR directory, relative to the project root", dir: true},
+	{Name: "collections", Kind: kindMap, Help: "named ADR logs, replacing directory", keys: append([]*Key{
+		{Name: "directory", Help: "ADR directory of the collection", dir: true, required: true},
+	}, idKeys()...)},
+	{Name: "default", Help: "collection used outside every collection's directory"},
+	{Name: "review", Kind: kindObject, Help: "approval of proposed ADRs with 'stamp review'", keys: []*Key{
+		{Name: "quorum", Kind: KindInt, Help: "approvals that accept an ADR (default: every requested reviewer)", check: checkPositive},
+	}},
+	{Name: "stale", Kind: kindObject, Help: "reminders of 'stamp stale'", keys: []*Key{
+		{Name: "days", Kind: KindInt, Help: "days a draft or proposed ADR may go without activity",
+			Default: strconv.Itoa(DefaultStaleDays), check: checkPositive},
+	}},
+}, append(idKeys(),
+	&Key{Name: "editor", Help: "command that opens ADRs", User: true},
+	&Key{Name: "author", Help: "author recorded in new ADRs", User: true},
+	&Key{Name: "graph_format", Help: "default output format of 'stamp graph'", User: true,
+		Default: DefaultSettings().GraphFormat, check: oneOf(GraphFormats...)},
+	&Key{Name: "check_updates", Kind: KindBool, Help: "report newer releases in 'stamp version'", User: true, Default: "false"},
+	&Key{Name: "theme", Help: "colors of the terminal, graphs and HTML export: a built-in or custom theme", User: true,
+		Default: DefaultSettings().Theme},
+	&Key{Name: "themes", Kind: kindMap, Help: "custom themes", User: true, keys: themeKeys()},
+)...)}
+
+// themeKeys are the keys of a custom theme
+func themeKeys() []*Key {
+	statuses := &Key{Name: "statuses", Kind: kindObject, Help: "colors of each status"}
+	for _, s := range adr.ValidStatuses {
+		statuses.keys = append(statuses.keys, &Key{Name: strings.ToLower(string(s)), Kind: kindObject, keys: []*Key{
+			{Name: "fill", Help: "badge and node background", check: checkColor},
+			{Name: "text", Help: "text on the fill", check: checkColor},
+			{Name: "stroke", Help: "graph node border", check: checkColor},
+		}})
+	}
+
+	keys := []*Key{
+		{Name: "base", Help: "theme the unset colors come from"},
+		statuses,
+	}
+	for _, name := range []string{"success", "warning", "error", "muted", "accent", "on_accent", "highlight"} {
+		keys = append(keys, &Key{Name: name, check: checkColor})
+	}
+	return keys
+}
+
+func checkColor(v string) error {
+	if !colorRegex.MatchString(v) {
+		return fmt.Errorf("invalid color %q (expected hex, like #0072b2)", v)
+	}
+	return nil
+}
+
+func checkPositive(v string) error {
+	if n, _ := strconv.Atoi(v); n < 1 {
+		return fmt.Errorf("invalid value %s (expected at least 1)", v)
+	}
+	return nil
+}
+
+func checkVersion(v string) error {
+	if n, _ := strconv.Atoi(v); n < 0 || n > CurrentVersion {
+		return fmt.Errorf("unsupported version %s (this stamp supports up to %d)", v, CurrentVersion)
+	}
+	return nil
+}
+
+func checkPrefix(v string) error {
+	if !prefixRegex.MatchString(v) {
+		return fmt.Errorf("invalid prefix %q (expected letters and digits, starting with a letter)", v)
+	}
+	return nil
+}
+
+func checkScheme(set func(*adr.Scheme, string)) func(string) error {
+	return func(v string) error {
+		var s adr.Scheme
+		set(&s, v)
+		return s.Validate()
+	}
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(v string) error {
+		if !slices.Contains(values, v) {
+			return fmt.Errorf("invalid value %q (expected %s)", v, strings.Join(values, ", "))
+		}
+		return nil
+	}
+}
+
+// LookupKey returns the schema of a dotted key such as "prefix" or
+// "collections.payments.directory"
+func LookupKey(name string) (*Key, error) {
+	key := schema
+	parts := strings.Split(name, ".")
+	for i := 0; i < len(parts); i++ {
+		switch key.Kind {
+		case kindMap:
+			// The part is an entry name; the next one is its key
+			if i+1 == len(parts) {
+				return nil, fmt.Errorf("%q is a mapping; set one of its keys, like %s.%s", name, name, key.keys[0].Name)
+			}
+			i++
+			fallthrough
+		case kindObject:
+			next := findKey(key.keys, parts[i])
+			if next == nil {
+				return nil, unknownKey(strings.Join(parts[:i+1], "."), parts[i], key.keys)
+			}
+			key = next
+		default:
+			return nil, fmt.Errorf("unknown key %q", name)
+		}
+	}
+	if key.Kind == kindObject || key.Kind == kindMap {
+		return nil, fmt.Errorf("%q is a mapping; set one of its keys", name)
+	}
+	return key, nil
+}
+
+// Parse converts a value given on the command line to the key's type
+func (k *Key) Parse(value string) (any, error) {
+	switch k.Kind {
+	case KindInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s (expected an integer)", value, k.Name)
+		}
+		return n, k.checkValue(value)
+	case KindBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s (expected true or false)", value, k.Name)
+		}
+		return b, nil
+	}
+	return value, k.checkValue(value)
+}
+
+func (k *Key) checkValue(value string) error {
+	if k.check == nil {
+		return nil
+	}
+	return k.check(value)
+}
+
+func findKey(keys []*Key, name string) *Key {
+	for _, k := range keys {
+		if k.Name == name {
+			return k
+		}
+	}
+	return nil
+}
+
+// unknownKey reports an unknown key, suggesting a close match
+func unknownKey(path, name string, keys []*Key) error {
+	best, bestDistance := "", 3
+	for _, k := range keys {
+		if d := editDistance(name, k.Name); d < bestDistance {
+			best, bestDistance = k.Name, d
+		}
+	}
+	if best != "" {
+		return fmt.Errorf("unknown key %q (did you mean %q?)", path, best)
+	}
+	return fmt.Errorf("unknown key %q", path)
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// Validate checks a project configuration file against the schema,
+// reporting unknown keys and invalid values with their position. If root is
+// not empty, directories are also checked to exist relative to it. The error
+// is only set when the file is not valid YAML.
+func Validate(data []byte, file, root string) ([]*Diagnostic, error) {
+	v := &validator{file: file, root: root}
+	if err := v.run(data, schema); err != nil {
+		return nil, err
+	}
+	return v.diagnostics, nil
+}
+
+// ValidateUser checks a user configuration file, which may only hold
+// personal settings
+func ValidateUser(data []byte, file string) ([]*Diagnostic, error) {
+	user := &Key{Kind: kindObject}
+	for _, k := range schema.keys {
+		if k.User {
+			user.keys = append(user.keys, k)
+		}
+	}
+	v := &validator{file: file, user: true}
+	if err := v.run(data, user); err != nil {
+		return nil, err
+	}
+	return v.diagnostics, nil
+}
+
+type validator struct {
+	file        string
+	root        string
+	user        bool
+	diagnostics []*Diagnostic
+}
+
+func (v *validator) run(data []byte, key *Key) error {
+	f, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return v.syntaxError(err)
+	}
+
+	for _, doc := range f.Docs {
+		if doc.Body == nil {
+			continue
+		}
+		values, ok := mappingValues(doc.Body)
+		if !ok {
+			v.report(doc.Body, "expected a mapping of settings")
+			continue
+		}
+		v.object(values, key, "", doc.Body)
+		if !v.user {
+			v.checkDefault(values)
+		}
+	}
+	return nil
+}
+
+// syntaxError turns a YAML parse error into a diagnostic
+func (v *validator) syntaxError(err error) error {
+	var yerr yaml.Error
+	if errors.As(err, &yerr) && yerr.GetToken() != nil {
+		pos := yerr.GetToken().Position
+		return &Diagnostic{File: v.file, Line: pos.Line, Column: pos.Column, Reason: yerr.GetMessage()}
+	}
+	return &Diagnostic{File: v.file, Reason: err.Error()}
+}
+
+func (v *validator) report(node ast.Node, format string, args ...any) {
+	d := &Diagnostic{File: v.file, Reason: fmt.Sprintf(format, args...)}
+	if tk := node.GetToken(); tk != nil {
+		d.Line, d.Column = tk.Position.Line, tk.Position.Column
+	}
+	v.diagnostics = append(v.diagnostics, d)
+}
+
+// object checks the keys of a mapping described by key. Missing required keys
+// are reported at owner, the key of the mapping.
+func (v *validator) object(values []*ast.MappingValueNode, key *Key, path string, owner ast.Node) {
+	seen := make(map[string]bool)
+	for _, mv := range values {
+		name := mv.Key.GetToken().Value
+		seen[name] = true
+		child := findKey(key.keys, name)
+		if child == nil {
+			if v.user && findKey(schema.keys, name) != nil {
+				v.report(mv.Key, "%q is a project setting and can't be set in the user configuration", path+name)
+				continue
+			}
+			v.report(mv.Key, "%s", unknownKey(path+name, name, key.keys).Error())
+			continue
+		}
+		v.value(mv.Value, child, path+name)
+	}
+
+	for _, k := range key.keys {
+		if k.required && !seen[k.Name] {
+			v.report(owner, "%s has no %s", strings.TrimSuffix(path, "."), k.Name)
+		}
+	}
+}
+
+// value checks a value against its key
+func (v *validator) value(node ast.Node, key *Key, path string) {
+	switch key.Kind {
+	case kindObject, kindMap:
+		values, ok := mappingValues(node)
+		if !ok {
+			if node.Type() != ast.NullType {
+				v.report(node, "%s must be a mapping", path)
+			}
+			return
+		}
+		if key.Kind == kindObject {
+			v.object(values, key, path+".", node)
+			return
+		}
+		for _, mv := range values {
+			name := mv.Key.GetToken().Value
+			entries, ok := mappingValues(mv.Value)
+			if !ok {
+				v.report(mv.Value, "%s.%s must be a mapping", path, name)
+				continue
+			}
+			v.object(entries, key, path+"."+name+".", mv.Key)
+		}
+		return
+	}
+
+	var text string
+	switch key.Kind {
+	case KindInt:
+		if node.Type() != ast.IntegerType {
+			v.report(node, "%s must be an integer", path)
+			return
+		}
+		text = node.GetToken().Value
+	case KindBool:
+		if node.Type() != ast.BoolType {
+			v.report(node, "%s must be true or false", path)
+			return
+		}
+		return
+	default:
+		switch node.Type() {
+		case ast.StringType, ast.LiteralType, ast.IntegerType, ast.FloatType:
+			text = node.GetToken().Value
+		default:
+			v.report(node, "%s must be a string", path)
+			return
+		}
+	}
+
+	if err := key.checkValue(text); err != nil {
+		v.report(node, "%s: %v", path, err)
+		return
+	}
+	if key.dir && v.root != "" {
+		if info, err := os.Stat(filepath.Join(v.root, text)); err != nil || !info.IsDir() {
+			v.report(node, "%s: directory %s does not exist", path, text)
+		}
+	}
+}
+
+// checkDefault reports a default collection that isn't configured
+func (v *validator) checkDefault(values []*ast.MappingValueNode) {
+	var def *ast.MappingValueNode
+	names := make(map[string]bool)
+	for _, mv := range values {
+		switch mv.Key.GetToken().Value {
+		case "default":
+			def = mv
+		case "collections":
+			entries, _ := mappingValues(mv.Value)
+			for _, e := range entries {
+				names[e.Key.GetToken().Value] = true
+			}
+		}
+	}
+	if def != nil && def.Value.GetToken() != nil && !names[def.Value.GetToken().Value] {
+		v.report(def.Value, "default: collection %q is not configured", def.Value.GetToken().Value)
+	}
+}
+
+// mappingValues returns the key/value pairs of a mapping node
+func mappingValues(node ast.Node) ([]*ast.MappingValueNode, bool) {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n.Values, true
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	data := []byte(`directroy: docs/adr
+width: wide
+collections:
+  payments:
+    prefix: 1PAY
+  billing:
+    directory: billing/adr
+default: platform
+graph_format: gif
+`)
+
+	diagnostics, err := Validate(data, ConfigFileName, "")
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+
+	want := []string{
+		`.stamp.yaml:1:1: unknown key "directroy" (did you mean "directory"?)`,
+		`.stamp.yaml:2:8: width must be an integer`,
+		`.stamp.yaml:5:13: collections.payments.prefix: invalid prefix "1PAY"`,
+		`.stamp.yaml:4:3: collections.payments has no directory`,
+		`.stamp.yaml:9:15: graph_format: invalid value "gif"`,
+		`.stamp.yaml:8:10: default: collection "platform" is not configured`,
+	}
+	if len(diagnostics) != len(want) {
+		t.Fatalf("Validate() returned %d diagnostics, want %d: %v", len(diagnostics), len(want), diagnostics)
+	}
+	for i, d := range diagnostics {
+		if !strings.HasPrefix(d.Error(), want[i]) {
+			t.Errorf("diagnostic %d = %q, want prefix %q", i, d.Error(), want[i])
+		}
+	}
+}
+
+func TestValidateDirectories(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "docs/adr"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	diagnostics, err := Validate([]byte("directory: docs/adr\n"), ConfigFileName, root)
+	if err != nil || len(diagnostics) != 0 {
+		t.Errorf("Validate() = %v, %v, want no problems", diagnostics, err)
+	}
+
+	diagnostics, err = Validate([]byte("directory: docs/decisions\n"), ConfigFileName, root)
+	if err != nil || len(diagnostics) != 1 || !strings.Contains(diagnostics[0].Reason, "does not exist") {
+		t.Errorf("Validate() = %v, %v, want missing directory", diagnostics, err)
+	}
+
+	// Without a root, directories are not checked
+	diagnostics, _ = Validate([]byte("directory: docs/decisions\n"), ConfigFileName, "")
+	if len(diagnostics) != 0 {
+		t.Errorf("Validate() without root = %v, want no problems", diagnostics)
+	}
+}
+
+func TestValidateSyntaxError(t *testing.T) {
+	_, err := Validate([]byte("directory: [docs\n"), ConfigFileName, "")
+	if err == nil {
+		t.Fatal("Validate() expected error for invalid YAML")
+	}
+	if !strings.HasPrefix(err.Error(), ".stamp.yaml:") {
+		t.Errorf("Validate() error = %q, want file position", err)
+	}
+}
+
+func TestValidateUser(t *testing.T) {
+	diagnostics, err := ValidateUser([]byte("editor: vim\ndirectory: docs/adr\ncheck_updates: yes please\n"), UserConfigFileName)
+	if err != nil {
+		t.Fatalf("ValidateUser() error: %v", err)
+	}
+	if len(diagnostics) != 2 {
+		t.Fatalf("ValidateUser() = %v, want 2 problems", diagnostics)
+	}
+	if !strings.Contains(diagnostics[0].Reason, "project setting") {
+		t.Errorf("diagnostic 0 = %q, want project setting", diagnostics[0].Reason)
+	}
+	if !strings.Contains(diagnostics[1].Reason, "true or false") {
+		t.Errorf("diagnostic 1 = %q, want boolean", diagnostics[1].Reason)
+	}
+}
+
+func TestValidateThemes(t *testing.T) {
+	data := []byte(`theme: team
+themes:
+  team:
+    base: colorblind
+    accent: teal
+    statuses:
+      accepted:
+        fill: "#112233"
+        border: "#000000"
+`)
+
+	diagnostics, err := ValidateUser(data, UserConfigFileName)
+	if err != nil {
+		t.Fatalf("ValidateUser() error: %v", err)
+	}
+
+	want := []string{
+		`config.yaml:5:13: themes.team.accent: invalid color "teal"`,
+		`config.yaml:9:9: unknown key "themes.team.statuses.accepted.border"`,
+	}
+	if len(diagnostics) != len(want) {
+		t.Fatalf("ValidateUser() returned %d diagnostics, want %d: %v", len(diagnostics), len(want), diagnostics)
+	}
+	for i, d := range diagnostics {
+		if !strings.HasPrefix(d.Error(), want[i]) {
+			t.Errorf("diagnostic %d = %q, want prefix %q", i, d.Error(), want[i])
+		}
+	}
+}
+
+func TestLoadUnknownKey(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	path := filepath.Join(dir, ConfigFileName)
+	if err := os.WriteFile(path, []byte("directroy: decisions\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadFile(path)
+	if err == nil || !strings.Contains(err.Error(), ":1:1: unknown key") {
+		t.Errorf("LoadFile() error = %v, want unknown key with position", err)
+	}
+}
+
+func TestLookupKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		wantErr string
+	}{
+		{"prefix", ""},
+		{"collections.payments.directory", ""},
+		{"collections.payments.width", ""},
+		{"editor", ""},
+		{"prefx", `did you mean "prefix"`},
+		{"collections", "mapping"},
+		{"collections.payments", "mapping"},
+		{"collections.payments.editor", "unknown key"},
+		{"prefix.value", "unknown key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			_, err := LookupKey(tt.key)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("LookupKey() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LookupKey() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyParse(t *testing.T) {
+	width, _ := LookupKey("width")
+	if v, err := width.Parse("3"); err != nil || v != 3 {
+		t.Errorf("Parse(3) = %v, %v", v, err)
+	}
+	if _, err := width.Parse("13"); err == nil {
+		t.Error("Parse(13) expected error for a width out of range")
+	}
+
+	check, _ := LookupKey("check_updates")
+	if v, err := check.Parse("true"); err != nil || v != true {
+		t.Errorf("Parse(true) = %v, %v", v, err)
+	}
+
+	filename, _ := LookupKey("filename")
+	if _, err := filename.Parse("{slug}.md"); err == nil {
+		t.Error("Parse({slug}.md) expected error for a template without {number}")
+	}
+}
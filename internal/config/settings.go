@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/goccy/go-yaml"
+)
+
+// UserConfigFileName is the name of the user configuration file in the
+// stamp directory of the user's config directory
+const UserConfigFileName = "config.yaml"
+
+// EnvConfig names an explicit project configuration file, like --config
+const EnvConfig = "STAMP_CONFIG"
+
+// Settings are personal preferences. They can be set in the user
+// configuration file, shared by every project, and overridden by the project
+// configuration and then by STAMP_* environment variables.
+type Settings struct {
+	// Editor opens ADRs, before $VISUAL and $EDITOR
+	Editor string `yaml:"editor,omitempty"`
+	// Author is recorded in new ADRs
+	Author string `yaml:"author,omitempty"`
+	// GraphFormat is the default output format of 'stamp graph'
+	GraphFormat string `yaml:"graph_format,omitempty"`
+	// CheckUpdates makes 'stamp version' report newer releases
+	CheckUpdates *bool `yaml:"check_updates,omitempty"`
+	// Theme names the colors of the terminal, graphs and the HTML export
+	Theme string `yaml:"theme,omitempty"`
+	// Themes are custom themes, selected by name like the built-in ones
+	Themes map[string]ThemeConfig `yaml:"themes,omitempty"`
+}
+
+// ThemeConfig is a custom theme. Colors are hex, like "#0072b2"; those left
+// out are taken from the base theme.
+type ThemeConfig struct {
+	// Base is the theme the colors default to, "dark" if unset
+	Base string `yaml:"base,omitempty"`
+	// Statuses are keyed by lowercase status name
+	Statuses  map[string]ThemeStatus `yaml:"statuses,omitempty"`
+	Success   string                 `yaml:"success,omitempty"`
+	Warning   string                 `yaml:"warning,omitempty"`
+	Error     string                 `yaml:"error,omitempty"`
+	Muted     string                 `yaml:"muted,omitempty"`
+	Accent    string                 `yaml:"accent,omitempty"`
+	OnAccent  string                 `yaml:"on_accent,omitempty"`
+	Highlight string                 `yaml:"highlight,omitempty"`
+}
+
+// ThemeStatus are the colors of a status in a custom theme
+type ThemeStatus struct {
+	Fill   string `yaml:"fill,omitempty"`
+	Text   string `yaml:"text,omitempty"`
+	Stroke string `yaml:"stroke,omitempty"`
+}
+
+// DefaultSettings returns the built-in settings, the lowest layer
+func DefaultSettings() Settings {
+	checkUpdates := false
+	return Settings{
+		GraphFormat:  "mermaid",
+		CheckUpdates: &checkUpdates,
+		Theme:        "dark",
+	}
+}
+
+// merge returns s with its unset fields taken from fallback
+func (s Settings) merge(fallback Settings) Settings {
+	if s.Editor == "" {
+		s.Editor = fallback.Editor
+	}
+	if s.Author == "" {
+		s.Author = fallback.Author
+	}
+	if s.GraphFormat == "" {
+		s.GraphFormat = fallback.GraphFormat
+	}
+	if s.CheckUpdates == nil {
+		s.CheckUpdates = fallback.CheckUpdates
+	}
+	if s.Theme == "" {
+		s.Theme = fallback.Theme
+	}
+	// Themes of the same name replace the fallback's
+	if len(fallback.Themes) > 0 {
+		themes := make(map[string]ThemeConfig, len(fallback.Themes)+len(s.Themes))
+		maps.Copy(themes, fallback.Themes)
+		maps.Copy(themes, s.Themes)
+		s.Themes = themes
+	}
+	return s
+}
+
+// applyEnv overrides settings with the STAMP_* environment variables
+func (s *Settings) applyEnv() error {
+	for name, field := range map[string]*string{
+		"STAMP_EDITOR":       &s.Editor,
+		"STAMP_AUTHOR":       &s.Author,
+		"STAMP_GRAPH_FORMAT": &s.GraphFormat,
+		"STAMP_THEME":        &s.Theme,
+	} {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			*field = value
+		}
+	}
+
+	if value, ok := os.LookupEnv("STAMP_CHECK_UPDATES"); ok && value != "" {
+		check, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("STAMP_CHECK_UPDATES: invalid boolean %q", value)
+		}
+		s.CheckUpdates = &check
+	}
+	return nil
+}
+
+// UserDir returns stamp's directory in the user's config directory,
+// $XDG_CONFIG_HOME/stamp if set
+func UserDir() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		var err error
+		dir, err = os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, "stamp"), nil
+}
+
+// UserConfigPath returns the path of the user configuration file
+func UserConfigPath() (string, error) {
+	dir, err := UserDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, UserConfigFileName), nil
+}
+
+// UserSettings reads the user configuration file. A missing file sets
+// nothing.
+func UserSettings() (Settings, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		// Without a home directory there is no user file to read
+		return Settings{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Settings{}, nil
+		}
+		return Settings{}, err
+	}
+
+	diagnostics, err := ValidateUser(data, path)
+	if err != nil {
+		return Settings{}, err
+	}
+	if len(diagnostics) > 0 {
+		return Settings{}, joinDiagnostics(diagnostics)
+	}
+
+	var s Settings
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return Settings{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// LoadSettings returns the settings outside of a project: the defaults, the
+// user configuration file and the environment
+func LoadSettings() (Settings, error) {
+	return layerSettings(Settings{})
+}
+
+// layerSettings stacks the project settings between the user configuration
+// file and the environment
+func layerSettings(project Settings) (Settings, error) {
+	user, err := UserSettings()
+	if err != nil {
+		return Settings{}, err
+	}
+
+	s := project.merge(user).merge(DefaultSettings())
+	if err := s.applyEnv(); err != nil {
+		return Settings{}, err
+	}
+	return s, nil
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeUserConfig points $XDG_CONFIG_HOME at a temp directory holding a user
+// configuration file with content
+func writeUserConfig(t *testing.T, content string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "stamp"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "stamp", UserConfigFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSettingsLayers(t *testing.T) {
+	writeUserConfig(t, "editor: vim\nauthor: Jane Doe\ngraph_format: dot\n")
+	for _, name := range []string{"STAMP_EDITOR", "STAMP_AUTHOR", "STAMP_GRAPH_FORMAT", "STAMP_CHECK_UPDATES"} {
+		t.Setenv(name, "")
+	}
+
+	s, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings() error: %v", err)
+	}
+	if s.Editor != "vim" || s.Author != "Jane Doe" || s.GraphFormat != "dot" {
+		t.Errorf("LoadSettings() = %+v, want the user file", s)
+	}
+	if s.CheckUpdates == nil || *s.CheckUpdates {
+		t.Errorf("CheckUpdates = %v, want default false", s.CheckUpdates)
+	}
+
+	// The project file overrides the user file
+	root := setupMonorepo(t, &Config{Directory: "docs/adr", Settings: Settings{GraphFormat: "svg"}})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Editor != "vim" || cfg.GraphFormat != "svg" {
+		t.Errorf("Load() settings = %+v, want user editor and project format", cfg.Settings)
+	}
+
+	// The environment overrides both
+	t.Setenv("STAMP_GRAPH_FORMAT", "d2")
+	t.Setenv("STAMP_CHECK_UPDATES", "true")
+	cfg, err = LoadFile(filepath.Join(root, ConfigFileName))
+	if err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+	if cfg.GraphFormat != "d2" || cfg.CheckUpdates == nil || !*cfg.CheckUpdates {
+		t.Errorf("LoadFile() settings = %+v, want environment overrides", cfg.Settings)
+	}
+
+	t.Setenv("STAMP_CHECK_UPDATES", "sometimes")
+	if _, err := LoadSettings(); err == nil {
+		t.Error("LoadSettings() expected error for an invalid boolean")
+	}
+}
+
+func TestLoadSettingsWithoutUserFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("STAMP_GRAPH_FORMAT", "")
+
+	s, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings() error: %v", err)
+	}
+	if s.GraphFormat != DefaultSettings().GraphFormat {
+		t.Errorf("GraphFormat = %q, want default %q", s.GraphFormat, DefaultSettings().GraphFormat)
+	}
+}
+
+func TestLoadSettingsThemes(t *testing.T) {
+	writeUserConfig(t, "theme: light\nthemes:\n  team:\n    accent: \"#111111\"\n  mine:\n    base: dark\n")
+	t.Setenv("STAMP_THEME", "")
+
+	// A project theme replaces the user theme of the same name
+	setupMonorepo(t, &Config{Directory: "docs/adr", Settings: Settings{
+		Theme:  "team",
+		Themes: map[string]ThemeConfig{"team": {Base: "colorblind"}},
+	}})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Theme != "team" {
+		t.Errorf("Theme = %q, want team", cfg.Theme)
+	}
+	if team := cfg.Themes["team"]; team.Base != "colorblind" || team.Accent != "" {
+		t.Errorf("Themes[team] = %+v, want the project's", team)
+	}
+	if _, ok := cfg.Themes["mine"]; !ok {
+		t.Errorf("Themes = %v, want the user's mine theme too", cfg.Themes)
+	}
+
+	t.Setenv("STAMP_THEME", "high-contrast")
+	s, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings() error: %v", err)
+	}
+	if s.Theme != "high-contrast" {
+		t.Errorf("Theme = %q, want STAMP_THEME", s.Theme)
+	}
+}
+
+func TestLoadExplicitFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "adr-config.yaml")
+	if err := os.WriteFile(path, []byte("directory: decisions\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// STAMP_CONFIG is used wherever stamp runs
+	t.Setenv(EnvConfig, path)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	adrDir, err := cfg.ADRDirectory()
+	if err != nil {
+		t.Fatalf("ADRDirectory() error: %v", err)
+	}
+	if want := filepath.Join(dir, "decisions"); adrDir != want {
+		t.Errorf("ADRDirectory() = %q, want %q", adrDir, want)
+	}
+}
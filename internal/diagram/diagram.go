@@ -0,0 +1,159 @@
+// Package diagram positions a directed graph on a 2D plane and renders it as
+// SVG or PNG without any external tools.
+package diagram
+
+import (
+	"github.com/stef16robbe/stamp/internal/layout"
+)
+
+// Sizes in pixels. Text metrics match the 7x13 font used for PNG output, so
+// SVG and PNG diagrams share the same geometry.
+const (
+	charWidth   = 7
+	fontSize    = 12
+	nodeHeight  = 36
+	nodePadding = 14
+	nodeGap     = 28
+	layerGap    = 64
+	margin      = 20
+	cornerRad   = 6
+)
+
+// NodeSpec describes a node to be laid out
+type NodeSpec struct {
+	ID     int
+	Label  string
+	Fill   string // hex color, e.g. "#22c55e"
+	Text   string // label color, white if empty
+	Stroke string
+}
+
+// textColor returns the label color of the node
+func (n NodeSpec) textColor() string {
+	if n.Text == "" {
+		return "#ffffff"
+	}
+	return n.Text
+}
+
+// EdgeSpec describes a directed edge between two nodes
+type EdgeSpec struct {
+	From   int
+	To     int
+	Label  string
+	Dashed bool
+}
+
+// Point is a position in pixels
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Node is a positioned node. X and Y are the top-left corner.
+type Node struct {
+	NodeSpec
+	X, Y, W, H float64
+}
+
+// Edge is a routed edge. Points runs from the source to the target.
+type Edge struct {
+	EdgeSpec
+	Points []Point
+}
+
+// Diagram is a fully positioned graph
+type Diagram struct {
+	Width  float64
+	Height float64
+	Nodes  []Node
+	Edges  []Edge
+}
+
+// Build lays out nodes and edges in layers from top to bottom
+func Build(nodes []NodeSpec, edges []EdgeSpec) *Diagram {
+	ids := make([]int, len(nodes))
+	specs := make(map[int]NodeSpec, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID
+		specs[n.ID] = n
+	}
+
+	layoutEdges := make([]layout.Edge, len(edges))
+	edgeSpecs := make(map[layout.Edge]EdgeSpec, len(edges))
+	for i, e := range edges {
+		layoutEdges[i] = layout.Edge{From: e.From, To: e.To}
+		edgeSpecs[layoutEdges[i]] = e
+	}
+
+	g := layout.Layered(ids, layoutEdges)
+
+	width := func(id int) float64 {
+		if layout.IsDummy(id) {
+			return 0
+		}
+		return float64(len([]rune(specs[id].Label))*charWidth + 2*nodePadding)
+	}
+
+	// Each layer is packed left to right and centered on the widest layer
+	layerWidths := make([]float64, len(g.Layers))
+	maxWidth := 0.0
+	for r, layer := range g.Layers {
+		for i, id := range layer {
+			if i > 0 {
+				layerWidths[r] += nodeGap
+			}
+			layerWidths[r] += width(id)
+		}
+		maxWidth = max(maxWidth, layerWidths[r])
+	}
+
+	centers := make(map[int]Point)
+	d := &Diagram{
+		Width:  maxWidth + 2*margin,
+		Height: float64(len(g.Layers))*(nodeHeight+layerGap) - layerGap + 2*margin,
+	}
+
+	for r, layer := range g.Layers {
+		x := margin + (maxWidth-layerWidths[r])/2
+		y := margin + float64(r)*(nodeHeight+layerGap)
+		for _, id := range layer {
+			w := width(id)
+			centers[id] = Point{X: x + w/2, Y: y + nodeHeight/2}
+			if !layout.IsDummy(id) {
+				d.Nodes = append(d.Nodes, Node{NodeSpec: specs[id], X: x, Y: y, W: w, H: nodeHeight})
+			}
+			x += w + nodeGap
+		}
+	}
+
+	for _, p := range g.Paths {
+		var points []Point
+		for i, id := range p.Nodes {
+			c := centers[id]
+			switch {
+			case layout.IsDummy(id):
+				points = append(points, c)
+			case i == 0:
+				points = append(points, Point{X: c.X, Y: c.Y + nodeHeight/2})
+			default:
+				points = append(points, Point{X: c.X, Y: c.Y - nodeHeight/2})
+			}
+		}
+		if p.Reversed {
+			for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+				points[i], points[j] = points[j], points[i]
+			}
+		}
+		d.Edges = append(d.Edges, Edge{EdgeSpec: edgeSpecs[p.Edge], Points: points})
+	}
+
+	return d
+}
+
+// labelPosition returns where an edge label is drawn: the middle of the
+// edge's first segment
+func (e Edge) labelPosition() Point {
+	a, b := e.Points[0], e.Points[1]
+	return Point{X: (a.X+b.X)/2 + 4, Y: (a.Y + b.Y) / 2}
+}
@@ -0,0 +1,129 @@
+package diagram
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func testDiagram() *Diagram {
+	nodes := []NodeSpec{
+		{ID: 1, Label: "0001: First", Fill: "#22c55e", Stroke: "#15803d"},
+		{ID: 2, Label: "0002: Second", Fill: "#3b82f6", Stroke: "#1d4ed8"},
+		{ID: 3, Label: "0003: Third & <last>", Fill: "#a855f7", Stroke: "#7e22ce"},
+	}
+	edges := []EdgeSpec{
+		{From: 2, To: 1, Label: "supersedes"},
+		{From: 3, To: 2, Label: "amends", Dashed: true},
+		{From: 3, To: 1, Label: "clarifies", Dashed: true},
+	}
+	return Build(nodes, edges)
+}
+
+func TestBuild(t *testing.T) {
+	d := testDiagram()
+
+	if len(d.Nodes) != 3 {
+		t.Fatalf("len(Nodes) = %d, want 3", len(d.Nodes))
+	}
+	if len(d.Edges) != 3 {
+		t.Fatalf("len(Edges) = %d, want 3", len(d.Edges))
+	}
+
+	y := make(map[int]float64)
+	for _, n := range d.Nodes {
+		if n.X < 0 || n.Y < 0 || n.X+n.W > d.Width || n.Y+n.H > d.Height {
+			t.Errorf("node %d lies outside the diagram", n.ID)
+		}
+		y[n.ID] = n.Y
+	}
+
+	// Sources are placed above their targets
+	if !(y[3] < y[2] && y[2] < y[1]) {
+		t.Errorf("unexpected vertical order: %v", y)
+	}
+
+	// The long edge 3 -> 1 is routed through a dummy node
+	for _, e := range d.Edges {
+		if e.From == 3 && e.To == 1 && len(e.Points) != 3 {
+			t.Errorf("edge 3 -> 1 has %d points, want 3", len(e.Points))
+		}
+	}
+}
+
+func TestSVG(t *testing.T) {
+	svg := testDiagram().SVG()
+
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		`fill="#22c55e"`,
+		`0003: Third &amp; &lt;last&gt;`,
+		`stroke-dasharray="6 4"`,
+		`marker-end="url(#arrow)"`,
+		`supersedes`,
+		`</svg>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG does not contain %q", want)
+		}
+	}
+}
+
+func TestPNG(t *testing.T) {
+	d := testDiagram()
+
+	var buf bytes.Buffer
+	if err := d.PNG(&buf); err != nil {
+		t.Fatalf("PNG() error: %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("failed to decode PNG: %v", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() < int(d.Width) || bounds.Dy() < int(d.Height) {
+		t.Errorf("image size = %dx%d, want at least %gx%g", bounds.Dx(), bounds.Dy(), d.Width, d.Height)
+	}
+
+	// The center of the first node is filled with its color
+	n := d.Nodes[0]
+	r, g, b, _ := img.At(int(n.X)+3, int(n.Y+n.H/2)).RGBA()
+	fill, _ := parseHex(n.Fill)
+	if uint8(r>>8) != fill.R || uint8(g>>8) != fill.G || uint8(b>>8) != fill.B {
+		t.Errorf("node pixel = (%d, %d, %d), want %v", r>>8, g>>8, b>>8, fill)
+	}
+}
+
+func TestPNGInvalidColor(t *testing.T) {
+	d := Build([]NodeSpec{{ID: 1, Label: "x", Fill: "green", Stroke: "#000000"}}, nil)
+
+	var buf bytes.Buffer
+	if err := d.PNG(&buf); err == nil {
+		t.Error("PNG() expected error for invalid color")
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{"#22c55e", false},
+		{"22c55e", false},
+		{"#fff", true},
+		{"#zzzzzz", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := parseHex(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseHex(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
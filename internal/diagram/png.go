@@ -0,0 +1,177 @@
+package diagram
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	edgeColor       = color.RGBA{0x4b, 0x55, 0x63, 0xff}
+	backgroundColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// PNG rasterizes the diagram and writes it as a PNG image
+func (d *Diagram) PNG(w io.Writer) error {
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(d.Width)), int(math.Ceil(d.Height))))
+	fillRect(img, img.Bounds(), backgroundColor)
+
+	for _, e := range d.Edges {
+		for i := 1; i < len(e.Points); i++ {
+			drawLine(img, e.Points[i-1], e.Points[i], 1.5, e.Dashed, edgeColor)
+		}
+		n := len(e.Points)
+		drawArrowhead(img, e.Points[n-2], e.Points[n-1], edgeColor)
+		if e.Label != "" {
+			pos := e.labelPosition()
+			drawText(img, pos.X, pos.Y, e.Label, edgeColor)
+		}
+	}
+
+	for _, n := range d.Nodes {
+		fill, err := parseHex(n.Fill)
+		if err != nil {
+			return err
+		}
+		stroke, err := parseHex(n.Stroke)
+		if err != nil {
+			return err
+		}
+		text, err := parseHex(n.textColor())
+		if err != nil {
+			return err
+		}
+		drawRoundedRect(img, n.X, n.Y, n.W, n.H, cornerRad, fill, stroke)
+		labelWidth := float64(len([]rune(n.Label)) * charWidth)
+		drawText(img, n.X+(n.W-labelWidth)/2, n.Y+n.H/2, n.Label, text)
+	}
+
+	return png.Encode(w, img)
+}
+
+// parseHex parses a "#rrggbb" color
+func parseHex(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color: %s", s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Set(x, y, c)
+		}
+	}
+}
+
+// insideRoundedRect reports whether the pixel center (px, py) lies inside the
+// rounded rectangle
+func insideRoundedRect(px, py, x, y, w, h, r float64) bool {
+	if px < x || px > x+w || py < y || py > y+h {
+		return false
+	}
+	cx := math.Min(math.Max(px, x+r), x+w-r)
+	cy := math.Min(math.Max(py, y+r), y+h-r)
+	return math.Hypot(px-cx, py-cy) <= r
+}
+
+func drawRoundedRect(img *image.RGBA, x, y, w, h, r float64, fill, stroke color.Color) {
+	for py := int(y); py <= int(math.Ceil(y+h)); py++ {
+		for px := int(x); px <= int(math.Ceil(x+w)); px++ {
+			cx, cy := float64(px)+0.5, float64(py)+0.5
+			if !insideRoundedRect(cx, cy, x, y, w, h, r) {
+				continue
+			}
+			if insideRoundedRect(cx, cy, x+1, y+1, w-2, h-2, r-1) {
+				img.Set(px, py, fill)
+			} else {
+				img.Set(px, py, stroke)
+			}
+		}
+	}
+}
+
+// drawLine draws a line of the given width from a to b. Dashed lines use the
+// same 6-on/4-off pattern as the SVG output.
+func drawLine(img *image.RGBA, a, b Point, width float64, dashed bool, c color.Color) {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+
+	minX := int(math.Floor(math.Min(a.X, b.X) - width))
+	maxX := int(math.Ceil(math.Max(a.X, b.X) + width))
+	minY := int(math.Floor(math.Min(a.Y, b.Y) - width))
+	maxY := int(math.Ceil(math.Max(a.Y, b.Y) + width))
+
+	for py := minY; py <= maxY; py++ {
+		for px := minX; px <= maxX; px++ {
+			cx, cy := float64(px)+0.5, float64(py)+0.5
+			// Project the pixel onto the segment
+			t := ((cx-a.X)*dx + (cy-a.Y)*dy) / (length * length)
+			if t < 0 || t > 1 {
+				continue
+			}
+			dist := math.Abs((cx-a.X)*dy-(cy-a.Y)*dx) / length
+			if dist > width/2 {
+				continue
+			}
+			if dashed && math.Mod(t*length, 10) >= 6 {
+				continue
+			}
+			img.Set(px, py, c)
+		}
+	}
+}
+
+// drawArrowhead draws a filled triangle at b pointing away from a
+func drawArrowhead(img *image.RGBA, a, b Point, c color.Color) {
+	const size = 8
+	angle := math.Atan2(b.Y-a.Y, b.X-a.X)
+	left := Point{X: b.X - size*math.Cos(angle-math.Pi/7), Y: b.Y - size*math.Sin(angle-math.Pi/7)}
+	right := Point{X: b.X - size*math.Cos(angle+math.Pi/7), Y: b.Y - size*math.Sin(angle+math.Pi/7)}
+
+	sign := func(p, q, r Point) float64 {
+		return (p.X-r.X)*(q.Y-r.Y) - (q.X-r.X)*(p.Y-r.Y)
+	}
+
+	for py := int(b.Y - size - 1); py <= int(b.Y+size+1); py++ {
+		for px := int(b.X - size - 1); px <= int(b.X+size+1); px++ {
+			p := Point{X: float64(px) + 0.5, Y: float64(py) + 0.5}
+			d1, d2, d3 := sign(p, b, left), sign(p, left, right), sign(p, right, b)
+			hasNeg := d1 < 0 || d2 < 0 || d3 < 0
+			hasPos := d1 > 0 || d2 > 0 || d3 > 0
+			if !hasNeg || !hasPos {
+				img.Set(px, py, c)
+			}
+		}
+	}
+}
+
+// drawText draws text with its left edge at x, vertically centered on y
+func drawText(img *image.RGBA, x, y float64, text string, c color.Color) {
+	face := basicfont.Face7x13
+	metrics := face.Metrics()
+	baseline := y + float64(metrics.Ascent.Round()-metrics.Descent.Round())/2
+
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(int(math.Round(x)), int(math.Round(baseline))),
+	}
+	drawer.DrawString(text)
+}
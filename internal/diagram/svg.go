@@ -0,0 +1,57 @@
+package diagram
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// SVG renders the diagram as a standalone SVG document
+func (d *Diagram) SVG() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g">`+"\n",
+		d.Width, d.Height, d.Width, d.Height)
+	sb.WriteString("  <defs>\n")
+	sb.WriteString(`    <marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse">` + "\n")
+	sb.WriteString(`      <path d="M 0 0 L 10 5 L 0 10 z" fill="#4b5563"/>` + "\n")
+	sb.WriteString("    </marker>\n")
+	sb.WriteString("  </defs>\n")
+	fmt.Fprintf(&sb, `  <rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+	fmt.Fprintf(&sb, `  <g font-family="monospace" font-size="%d">`+"\n", fontSize)
+
+	for _, e := range d.Edges {
+		points := make([]string, len(e.Points))
+		for i, p := range e.Points {
+			points[i] = fmt.Sprintf("%g,%g", p.X, p.Y)
+		}
+		dash := ""
+		if e.Dashed {
+			dash = ` stroke-dasharray="6 4"`
+		}
+		fmt.Fprintf(&sb, `    <polyline points="%s" fill="none" stroke="#4b5563" stroke-width="1.5"%s marker-end="url(#arrow)"/>`+"\n",
+			strings.Join(points, " "), dash)
+		if e.Label != "" {
+			pos := e.labelPosition()
+			fmt.Fprintf(&sb, `    <text x="%g" y="%g" fill="#4b5563" dominant-baseline="middle">%s</text>`+"\n",
+				pos.X, pos.Y, escape(e.Label))
+		}
+	}
+
+	for _, n := range d.Nodes {
+		fmt.Fprintf(&sb, `    <rect x="%g" y="%g" width="%g" height="%g" rx="%d" fill="%s" stroke="%s"/>`+"\n",
+			n.X, n.Y, n.W, n.H, cornerRad, n.Fill, n.Stroke)
+		fmt.Fprintf(&sb, `    <text x="%g" y="%g" fill="%s" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n",
+			n.X+n.W/2, n.Y+n.H/2, n.textColor(), escape(n.Label))
+	}
+
+	sb.WriteString("  </g>\n")
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func escape(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
@@ -0,0 +1,363 @@
+// Package gitfs provides read-only access to a directory as it existed at a
+// git revision, read from the local object database without checking it out.
+package gitfs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os/exec"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FS is an fs.FS over a git tree. The tree is listed once when the FS is
+// created; file contents are read on demand through a single long-running
+// "git cat-file --batch" process, which Close stops.
+type FS struct {
+	dir     string // directory git runs in
+	commit  string // resolved commit hash
+	root    string // path of the tree within the repository, "" for the top level
+	modTime time.Time
+
+	entries  map[string]*entry        // by path relative to root, "." for root
+	children map[string][]fs.DirEntry // directory path -> sorted entries
+
+	mu    sync.Mutex
+	batch *catFile
+}
+
+// entry is a file or directory in the tree
+type entry struct {
+	info   *fileInfo
+	object string // object hash
+}
+
+// New returns the tree of the directory root, relative to the existing
+// working directory dir, as of rev (a commit, tag, branch or any other
+// revision git understands)
+func New(dir, rev, root string) (*FS, error) {
+	if _, err := git(dir, "rev-parse", "--git-dir"); err != nil {
+		return nil, fmt.Errorf("%s is not in a git repository", dir)
+	}
+
+	out, err := git(dir, "rev-parse", "--verify", "--quiet", "--end-of-options", rev+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision %q", rev)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	prefix, err := git(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+
+	out, err = git(dir, "show", "-s", "--format=%cI", commit)
+	if err != nil {
+		return nil, err
+	}
+	modTime, _ := time.Parse(time.RFC3339, strings.TrimSpace(string(out)))
+
+	fsys := &FS{
+		dir:     dir,
+		commit:  commit,
+		root:    strings.Trim(path.Join(strings.TrimSpace(string(prefix)), root), "/"),
+		modTime: modTime,
+	}
+	if fsys.root == "." {
+		fsys.root = ""
+	}
+
+	out, err = git(dir, "ls-tree", "-r", "-t", "-z", "--long", "--full-tree", fsys.commit+":"+fsys.root)
+	if err != nil {
+		return nil, fmt.Errorf("%s does not exist at %s", root, rev)
+	}
+	fsys.index(out)
+
+	return fsys, nil
+}
+
+// Commit returns the hash of the commit the file system reads from
+func (f *FS) Commit() string {
+	return f.commit
+}
+
+// Close stops the git process reading file contents, if one was started
+func (f *FS) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.batch == nil {
+		return nil
+	}
+	err := f.batch.close()
+	f.batch = nil
+	return err
+}
+
+// git runs git in dir and returns its standard output
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// index builds the entries of the FS from recursive 'git ls-tree' output
+func (f *FS) index(out []byte) {
+	f.entries = map[string]*entry{
+		".": {info: &fileInfo{name: ".", mode: fs.ModeDir | 0555, modTime: f.modTime}},
+	}
+	f.children = map[string][]fs.DirEntry{".": nil}
+
+	for _, record := range strings.Split(string(out), "\x00") {
+		// <mode> SP <type> SP <object> SP+ <size> TAB <path>
+		meta, name, ok := strings.Cut(record, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 4 {
+			continue
+		}
+
+		info := &fileInfo{name: path.Base(name), modTime: f.modTime}
+		switch fields[1] {
+		case "tree":
+			info.mode = fs.ModeDir | 0555
+			f.children[name] = nil
+		case "blob":
+			info.mode = 0444
+			info.size, _ = strconv.ParseInt(fields[3], 10, 64)
+			if fields[0] == "120000" {
+				info.mode |= fs.ModeSymlink
+			}
+		default:
+			// Submodules and other object types have no file content
+			continue
+		}
+
+		f.entries[name] = &entry{info: info, object: fields[2]}
+		dir := path.Dir(name)
+		f.children[dir] = append(f.children[dir], fs.FileInfoToDirEntry(info))
+	}
+
+	for _, entries := range f.children {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Name() < entries[j].Name()
+		})
+	}
+}
+
+// lookup returns the entry at name, or fs.ErrNotExist
+func (f *FS) lookup(name string) (*entry, error) {
+	e, ok := f.entries[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return e, nil
+}
+
+// readBlob reads the content of a blob through the cat-file process,
+// starting it on first use
+func (f *FS) readBlob(object string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.batch == nil {
+		batch, err := startCatFile(f.dir)
+		if err != nil {
+			return nil, err
+		}
+		f.batch = batch
+	}
+
+	data, err := f.batch.read(object)
+	if err != nil {
+		// The stream may be out of sync; start over on the next read
+		_ = f.batch.close()
+		f.batch = nil
+		return nil, err
+	}
+	return data, nil
+}
+
+func (f *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	e, err := f.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	info := *e.info
+	info.name = path.Base(name)
+	if info.IsDir() {
+		return &dirFile{info: &info, entries: f.children[name]}, nil
+	}
+
+	data, err := f.readBlob(e.object)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &file{info: &info, Reader: bytes.NewReader(data)}, nil
+}
+
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+
+	e, err := f.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	if e.info.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errors.New("is a directory")}
+	}
+
+	data, err := f.readBlob(e.object)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	return data, nil
+}
+
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	e, err := f.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	if !e.info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	return slices.Clone(f.children[name]), nil
+}
+
+// catFile is a running "git cat-file --batch" process
+type catFile struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+func startCatFile(dir string) (*catFile, error) {
+	cmd := exec.Command("git", "-C", dir, "cat-file", "--batch")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &catFile{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout)}, nil
+}
+
+// read returns the content of object
+func (c *catFile) read(object string) ([]byte, error) {
+	if _, err := io.WriteString(c.stdin, object+"\n"); err != nil {
+		return nil, err
+	}
+
+	// <object> SP <type> SP <size> LF <content> LF, or <object> SP missing LF
+	header, err := c.stdout.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("git cat-file: %s", strings.TrimSpace(header))
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: invalid header %q", strings.TrimSpace(header))
+	}
+
+	data := make([]byte, size+1)
+	if _, err := io.ReadFull(c.stdout, data); err != nil {
+		return nil, err
+	}
+	return data[:size], nil
+}
+
+func (c *catFile) close() error {
+	_ = c.stdin.Close()
+	return c.cmd.Wait()
+}
+
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) Mode() fs.FileMode  { return i.mode }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *fileInfo) Sys() any           { return nil }
+
+type file struct {
+	*bytes.Reader
+	info *fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *file) Close() error               { return nil }
+
+type dirFile struct {
+	info    *fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *dirFile) Close() error               { return nil }
+
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
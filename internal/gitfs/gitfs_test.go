@@ -0,0 +1,181 @@
+package gitfs
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// testRepo creates a git repository with two commits tagged v1 and v2
+func testRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("docs/adr/0001-first.md", "# 1. First\n")
+	write("README.md", "readme\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "first")
+	run("tag", "v1")
+
+	write("docs/adr/0001-first.md", "# 1. First, revised\n")
+	write("docs/adr/0002-second.md", "# 2. Second\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "second")
+	run("tag", "v2")
+
+	return dir
+}
+
+func TestFS(t *testing.T) {
+	dir := testRepo(t)
+
+	fsys, err := New(dir, "v1", "docs/adr")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	defer fsys.Close()
+
+	if err := fstest.TestFS(fsys, "0001-first.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := fs.ReadFile(fsys, "0001-first.md")
+	if err != nil {
+		t.Fatalf("ReadFile() error: %v", err)
+	}
+	if string(data) != "# 1. First\n" {
+		t.Errorf("ReadFile() = %q, want the content at v1", data)
+	}
+
+	if _, err := fs.ReadFile(fsys, "0002-second.md"); !os.IsNotExist(err) {
+		t.Errorf("ReadFile() of a later file error = %v, want not exist", err)
+	}
+}
+
+func TestFSTopLevel(t *testing.T) {
+	dir := testRepo(t)
+
+	fsys, err := New(dir, "v2", "")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	defer fsys.Close()
+
+	if err := fstest.TestFS(fsys, "README.md", "docs/adr/0001-first.md", "docs/adr/0002-second.md"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSFromSubdirectory(t *testing.T) {
+	dir := testRepo(t)
+
+	// Paths are relative to the directory git runs in
+	fsys, err := New(filepath.Join(dir, "docs"), "v2", "adr")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	defer fsys.Close()
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		t.Fatalf("ReadDir() error: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Name() != "0002-second.md" {
+		t.Errorf("ReadDir() = %v, want two ADRs", entries)
+	}
+}
+
+func TestFSReadsThroughOneProcess(t *testing.T) {
+	dir := testRepo(t)
+
+	fsys, err := New(dir, "v2", "docs/adr")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	defer fsys.Close()
+
+	if fsys.batch != nil {
+		t.Error("New() started cat-file before any file was read")
+	}
+
+	want := map[string]string{
+		"0001-first.md":  "# 1. First, revised\n",
+		"0002-second.md": "# 2. Second\n",
+	}
+	var batch *catFile
+	for i := 0; i < 3; i++ {
+		for name, content := range want {
+			data, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				t.Fatalf("ReadFile(%s) error: %v", name, err)
+			}
+			if string(data) != content {
+				t.Errorf("ReadFile(%s) = %q, want %q", name, data, content)
+			}
+			if batch == nil {
+				batch = fsys.batch
+			}
+			if fsys.batch != batch {
+				t.Fatal("ReadFile() started another cat-file process")
+			}
+		}
+	}
+
+	if err := fsys.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if fsys.batch != nil {
+		t.Error("Close() left cat-file running")
+	}
+
+	// Reading after Close starts a new process
+	if _, err := fs.ReadFile(fsys, "0001-first.md"); err != nil {
+		t.Errorf("ReadFile() after Close() error: %v", err)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	dir := testRepo(t)
+
+	if _, err := New(dir, "v9", "docs/adr"); err == nil {
+		t.Error("New() expected error for unknown revision")
+	}
+	if _, err := New(dir, "v1", "missing"); err == nil {
+		t.Error("New() expected error for missing directory")
+	}
+	if _, err := New(dir, "v1", "README.md"); err == nil {
+		t.Error("New() expected error for a file")
+	}
+	if _, err := New(t.TempDir(), "HEAD", ""); err == nil {
+		t.Error("New() expected error outside a repository")
+	}
+}
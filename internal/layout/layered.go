@@ -0,0 +1,276 @@
+// Package layout computes layered (Sugiyama-style) arrangements of directed
+// graphs for renderers that need to position nodes themselves.
+package layout
+
+import (
+	"slices"
+	"sort"
+)
+
+// Edge is a directed edge between two node IDs
+type Edge struct {
+	From int
+	To   int
+}
+
+// Path is the route of an edge through the layers. Nodes holds the source,
+// any dummy nodes and the target, ordered from the upper to the lower layer.
+// Reversed is set when the edge had to be flipped to break a cycle, in which
+// case Nodes runs from the edge's target to its source.
+type Path struct {
+	Edge     Edge
+	Nodes    []int
+	Reversed bool
+}
+
+// Graph is the result of a layered layout
+type Graph struct {
+	// Layers holds the node IDs of each layer from top to bottom, ordered
+	// left to right. Dummy nodes inserted for long edges have negative IDs.
+	Layers [][]int
+	// Paths holds one entry per laid out edge, in input order
+	Paths []Path
+
+	rank map[int]int
+}
+
+// Rank returns the layer index of a node
+func (g *Graph) Rank(id int) int {
+	return g.rank[id]
+}
+
+// IsDummy reports whether id is a dummy node inserted by the layout
+func IsDummy(id int) bool {
+	return id < 0
+}
+
+// sweeps is the number of barycenter passes used to reduce edge crossings
+const sweeps = 8
+
+// Layered arranges nodes into layers so that every edge points downwards,
+// inserting dummy nodes for edges spanning more than one layer and ordering
+// each layer to reduce crossings. Edges referencing unknown nodes, self-loops
+// and duplicate edges are ignored.
+func Layered(nodes []int, edges []Edge) *Graph {
+	known := make(map[int]bool, len(nodes))
+	for _, n := range nodes {
+		known[n] = true
+	}
+
+	g := &Graph{rank: make(map[int]int)}
+
+	// Drop edges that cannot be laid out
+	seen := make(map[Edge]bool)
+	var valid []Edge
+	for _, e := range edges {
+		if !known[e.From] || !known[e.To] || e.From == e.To || seen[e] {
+			continue
+		}
+		seen[e] = true
+		valid = append(valid, e)
+	}
+
+	reversed := breakCycles(nodes, valid)
+
+	// Orient every edge downwards
+	oriented := make([]Edge, len(valid))
+	for i, e := range valid {
+		if reversed[e] {
+			e = Edge{From: e.To, To: e.From}
+		}
+		oriented[i] = e
+	}
+
+	assignRanks(nodes, oriented, g.rank)
+
+	// Build layers, splitting long edges with dummy nodes
+	maxRank := 0
+	for _, n := range nodes {
+		maxRank = max(maxRank, g.rank[n])
+	}
+	g.Layers = make([][]int, maxRank+1)
+	for _, n := range nodes {
+		g.Layers[g.rank[n]] = append(g.Layers[g.rank[n]], n)
+	}
+
+	nextDummy := -1
+	for i, e := range valid {
+		o := oriented[i]
+		path := []int{o.From}
+		for r := g.rank[o.From] + 1; r < g.rank[o.To]; r++ {
+			d := nextDummy
+			nextDummy--
+			g.rank[d] = r
+			g.Layers[r] = append(g.Layers[r], d)
+			path = append(path, d)
+		}
+		path = append(path, o.To)
+		g.Paths = append(g.Paths, Path{Edge: e, Nodes: path, Reversed: reversed[e]})
+	}
+
+	g.orderLayers()
+
+	return g
+}
+
+// breakCycles returns the set of edges that must be reversed to make the
+// graph acyclic, found with a depth-first search in node order.
+func breakCycles(nodes []int, edges []Edge) map[Edge]bool {
+	out := make(map[int][]int)
+	for _, e := range edges {
+		out[e.From] = append(out[e.From], e.To)
+	}
+
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := make(map[int]int)
+	reversed := make(map[Edge]bool)
+
+	var visit func(n int)
+	visit = func(n int) {
+		state[n] = active
+		for _, m := range out[n] {
+			switch state[m] {
+			case unvisited:
+				visit(m)
+			case active:
+				reversed[Edge{From: n, To: m}] = true
+			}
+		}
+		state[n] = done
+	}
+
+	for _, n := range nodes {
+		if state[n] == unvisited {
+			visit(n)
+		}
+	}
+
+	return reversed
+}
+
+// assignRanks places every node one layer below its lowest predecessor
+// (longest-path layering). edges must be acyclic.
+func assignRanks(nodes []int, edges []Edge, rank map[int]int) {
+	indegree := make(map[int]int)
+	out := make(map[int][]int)
+	for _, e := range edges {
+		indegree[e.To]++
+		out[e.From] = append(out[e.From], e.To)
+	}
+
+	var queue []int
+	for _, n := range nodes {
+		rank[n] = 0
+		if indegree[n] == 0 {
+			queue = append(queue, n)
+		}
+	}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, m := range out[n] {
+			rank[m] = max(rank[m], rank[n]+1)
+			indegree[m]--
+			if indegree[m] == 0 {
+				queue = append(queue, m)
+			}
+		}
+	}
+}
+
+// orderLayers reorders the nodes within each layer using the barycenter
+// heuristic, sweeping down and up several times.
+func (g *Graph) orderLayers() {
+	up := make(map[int][]int)
+	down := make(map[int][]int)
+	for _, p := range g.Paths {
+		for i := 1; i < len(p.Nodes); i++ {
+			down[p.Nodes[i-1]] = append(down[p.Nodes[i-1]], p.Nodes[i])
+			up[p.Nodes[i]] = append(up[p.Nodes[i]], p.Nodes[i-1])
+		}
+	}
+
+	position := make(map[int]int)
+	record := func(layer []int) {
+		for i, n := range layer {
+			position[n] = i
+		}
+	}
+	for _, layer := range g.Layers {
+		record(layer)
+	}
+
+	reorder := func(layer []int, neighbors map[int][]int) {
+		center := make(map[int]float64, len(layer))
+		for _, n := range layer {
+			adj := neighbors[n]
+			if len(adj) == 0 {
+				center[n] = float64(position[n])
+				continue
+			}
+			sum := 0
+			for _, m := range adj {
+				sum += position[m]
+			}
+			center[n] = float64(sum) / float64(len(adj))
+		}
+		sort.SliceStable(layer, func(i, j int) bool {
+			return center[layer[i]] < center[layer[j]]
+		})
+		record(layer)
+	}
+
+	best := cloneLayers(g.Layers)
+	bestCrossings := g.crossings(down, position)
+
+	for range sweeps {
+		for r := 1; r < len(g.Layers); r++ {
+			reorder(g.Layers[r], up)
+		}
+		for r := len(g.Layers) - 2; r >= 0; r-- {
+			reorder(g.Layers[r], down)
+		}
+		if c := g.crossings(down, position); c < bestCrossings {
+			best = cloneLayers(g.Layers)
+			bestCrossings = c
+		}
+	}
+
+	g.Layers = best
+}
+
+// crossings counts the edge crossings between adjacent layers
+func (g *Graph) crossings(down map[int][]int, position map[int]int) int {
+	total := 0
+	for r := 0; r+1 < len(g.Layers); r++ {
+		type segment struct{ from, to int }
+		var segments []segment
+		for _, n := range g.Layers[r] {
+			for _, m := range down[n] {
+				segments = append(segments, segment{position[n], position[m]})
+			}
+		}
+		for i := range segments {
+			for j := i + 1; j < len(segments); j++ {
+				a, b := segments[i], segments[j]
+				if (a.from < b.from && a.to > b.to) || (a.from > b.from && a.to < b.to) {
+					total++
+				}
+			}
+		}
+	}
+	return total
+}
+
+func cloneLayers(layers [][]int) [][]int {
+	out := make([][]int, len(layers))
+	for i, layer := range layers {
+		out[i] = slices.Clone(layer)
+	}
+	return out
+}
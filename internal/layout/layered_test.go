@@ -0,0 +1,109 @@
+package layout
+
+import (
+	"testing"
+)
+
+func TestLayeredRanks(t *testing.T) {
+	// 3 -> 2 -> 1, 4 -> 1
+	g := Layered([]int{1, 2, 3, 4}, []Edge{{3, 2}, {2, 1}, {4, 1}})
+
+	tests := []struct {
+		node int
+		want int
+	}{
+		{3, 0},
+		{4, 0},
+		{2, 1},
+		{1, 2},
+	}
+
+	for _, tt := range tests {
+		if got := g.Rank(tt.node); got != tt.want {
+			t.Errorf("Rank(%d) = %d, want %d", tt.node, got, tt.want)
+		}
+	}
+
+	if len(g.Layers) != 3 {
+		t.Fatalf("len(Layers) = %d, want 3", len(g.Layers))
+	}
+}
+
+func TestLayeredInsertsDummyNodes(t *testing.T) {
+	// 4 -> 1 spans two layers because of 4 -> 3 -> 2 -> 1
+	g := Layered([]int{1, 2, 3, 4}, []Edge{{4, 3}, {3, 2}, {2, 1}, {4, 1}})
+
+	var long *Path
+	for i := range g.Paths {
+		if g.Paths[i].Edge == (Edge{4, 1}) {
+			long = &g.Paths[i]
+		}
+	}
+	if long == nil {
+		t.Fatal("no path for edge 4 -> 1")
+	}
+
+	if len(long.Nodes) != 4 {
+		t.Fatalf("path length = %d, want 4 (source, two dummies, target)", len(long.Nodes))
+	}
+	for _, id := range long.Nodes[1:3] {
+		if !IsDummy(id) {
+			t.Errorf("node %d should be a dummy", id)
+		}
+	}
+
+	// Every path must only connect adjacent layers
+	for _, p := range g.Paths {
+		for i := 1; i < len(p.Nodes); i++ {
+			if g.Rank(p.Nodes[i]) != g.Rank(p.Nodes[i-1])+1 {
+				t.Errorf("path %v skips a layer between %d and %d", p.Edge, p.Nodes[i-1], p.Nodes[i])
+			}
+		}
+	}
+}
+
+func TestLayeredBreaksCycles(t *testing.T) {
+	g := Layered([]int{1, 2, 3}, []Edge{{1, 2}, {2, 3}, {3, 1}})
+
+	if len(g.Paths) != 3 {
+		t.Fatalf("len(Paths) = %d, want 3", len(g.Paths))
+	}
+
+	reversed := 0
+	for _, p := range g.Paths {
+		if p.Reversed {
+			reversed++
+		}
+		first, last := p.Nodes[0], p.Nodes[len(p.Nodes)-1]
+		if g.Rank(first) >= g.Rank(last) {
+			t.Errorf("path %v does not point downwards", p.Edge)
+		}
+	}
+	if reversed != 1 {
+		t.Errorf("reversed edges = %d, want 1", reversed)
+	}
+}
+
+func TestLayeredIgnoresInvalidEdges(t *testing.T) {
+	g := Layered([]int{1, 2}, []Edge{{1, 1}, {1, 99}, {2, 1}, {2, 1}})
+
+	if len(g.Paths) != 1 {
+		t.Errorf("len(Paths) = %d, want 1", len(g.Paths))
+	}
+}
+
+func TestLayeredReducesCrossings(t *testing.T) {
+	// Two parallel chains whose targets start out in crossed order
+	g := Layered([]int{1, 2, 3, 4}, []Edge{{1, 4}, {2, 3}})
+
+	pos := make(map[int]int)
+	for _, layer := range g.Layers {
+		for i, id := range layer {
+			pos[id] = i
+		}
+	}
+
+	if (pos[1] < pos[2]) != (pos[4] < pos[3]) {
+		t.Errorf("layers %v contain a crossing", g.Layers)
+	}
+}
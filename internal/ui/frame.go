@@ -9,12 +9,12 @@ import (
 // Frame wraps content in a styled border with a title
 func Frame(title string, content string) string {
 	titleStyle := lipgloss.NewStyle().
-		Foreground(Cyan).
+		Foreground(AccentColor).
 		Bold(true)
 
 	frameStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(Gray).
+		Border(FrameBorder).
+		BorderForeground(MutedColor).
 		Padding(1, 2)
 
 	header := titleStyle.Render(fmt.Sprintf("─ %s ", title))
@@ -40,8 +40,8 @@ func Frame(title string, content string) string {
 // SimpleFrame wraps content in a simple border
 func SimpleFrame(content string) string {
 	return lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(Gray).
+		Border(FrameBorder).
+		BorderForeground(MutedColor).
 		Padding(0, 1).
 		Render(content)
 }
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/colorprofile"
+)
+
+// Stdout and Stderr print styled text, downsampling colors to what the
+// terminal supports and removing all styling when color is off. Raw output,
+// such as images and completion scripts, goes to os.Stdout instead.
+var (
+	Stdout = colorprofile.NewWriter(os.Stdout, os.Environ())
+	Stderr = colorprofile.NewWriter(os.Stderr, os.Environ())
+)
+
+// ColorModes are the values of --color
+var ColorModes = []string{"auto", "always", "never"}
+
+func init() {
+	applyTheme(current)
+	_ = SetColor("auto")
+}
+
+// SetColor chooses when output is styled. "auto" styles a terminal unless
+// NO_COLOR is set, CLICOLOR=0 or TERM=dumb; "always" and "never" override
+// the detection. Without color, icons are ASCII too.
+func SetColor(mode string) error {
+	switch mode {
+	case "auto":
+		Stdout.Profile = detectProfile(os.Stdout)
+		Stderr.Profile = detectProfile(os.Stderr)
+	case "always":
+		Stdout.Profile = max(colorprofile.Detect(os.Stdout, os.Environ()), colorprofile.ANSI256)
+		Stderr.Profile = max(colorprofile.Detect(os.Stderr, os.Environ()), colorprofile.ANSI256)
+	case "never":
+		Stdout.Profile = colorprofile.NoTTY
+		Stderr.Profile = colorprofile.NoTTY
+	default:
+		return fmt.Errorf("invalid color mode: %s (valid: %s)", mode, strings.Join(ColorModes, ", "))
+	}
+	setIcons(HasColor())
+	return nil
+}
+
+// detectProfile returns the profile of f from the environment. Terminals
+// without color get plain text rather than bold and underlines.
+func detectProfile(f *os.File) colorprofile.Profile {
+	profile := colorprofile.Detect(f, os.Environ())
+	if profile <= colorprofile.ASCII {
+		return colorprofile.NoTTY
+	}
+	return profile
+}
+
+// HasColor reports whether stdout is styled
+func HasColor() bool {
+	return Stdout.Profile > colorprofile.NoTTY
+}
+
+// Plain removes the styling of content that stdout doesn't support, for
+// output that bypasses Stdout such as a pager's input
+func Plain(content string) string {
+	var sb strings.Builder
+	w := &colorprofile.Writer{Forward: &sb, Profile: Stdout.Profile}
+	_, _ = w.WriteString(content)
+	return sb.String()
+}
+
+// setIcons uses Unicode icons and borders with color, and ASCII without
+func setIcons(unicode bool) {
+	success, warning, failure, arrow := "✓", "⚠", "✗", "→"
+	border := lipgloss.RoundedBorder()
+	if !unicode {
+		success, warning, failure, arrow = "+", "!", "x", "->"
+		border = lipgloss.ASCIIBorder()
+	}
+	TableBorder, FrameBorder = border, border
+	SuccessIcon = SuccessStyle.Render(success)
+	WarningIcon = WarningStyle.Render(warning)
+	ErrorIcon = ErrorStyle.Render(failure)
+	ArrowIcon = MutedStyle.Render(arrow)
+	Arrow = arrow
+	unicodeGlyphs = unicode
+}
+
+// unicodeGlyphs is whether diagrams are drawn with Unicode glyphs, set with
+// the icons
+var unicodeGlyphs = true
+
+// asciiGlyphs replaces the line-drawing and arrow glyphs of diagrams when
+// icons are ASCII
+var asciiGlyphs = map[rune]rune{
+	'│': '|', '┆': ':', '─': '-', '┄': '.',
+	'╭': '+', '╮': '+', '╰': '+', '╯': '+',
+	'├': '+', '┤': '+', '┬': '+', '┴': '+', '┼': '+',
+	'▲': '^', '▼': 'v', '▶': '>',
+}
+
+// Glyph returns r, or its ASCII replacement when icons are ASCII
+func Glyph(r rune) rune {
+	if ascii, ok := asciiGlyphs[r]; ok && !unicodeGlyphs {
+		return ascii
+	}
+	return r
+}
+
+// Glyphs replaces the diagram glyphs of s like Glyph
+func Glyphs(s string) string {
+	if unicodeGlyphs {
+		return s
+	}
+	return strings.Map(Glyph, s)
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+)
+
+// Page prints content through $PAGER (less by default) when stdout is a
+// terminal too short to show it at once, and directly otherwise or when
+// $PAGER is blank
+func Page(content string) error {
+	_, height := Size(os.Stdout)
+	if !IsTerminal(os.Stdout) || lipgloss.Height(content) < height {
+		_, err := fmt.Fprintln(Stdout, content)
+		return err
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	args := strings.Fields(pager)
+	if len(args) == 0 {
+		// A blank $PAGER turns paging off
+		_, err := fmt.Fprintln(Stdout, content)
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(Plain(content) + "\n")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		// Keep colors, and leave the text on screen after quitting
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			_, err := fmt.Fprintln(Stdout, content)
+			return err
+		}
+		return fmt.Errorf("pager %s failed: %w", args[0], err)
+	}
+	return nil
+}
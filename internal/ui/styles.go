@@ -1,51 +1,76 @@
 package ui
 
 import (
+	"image/color"
+
 	"charm.land/lipgloss/v2"
 	"github.com/stef16robbe/stamp/internal/adr"
 )
 
+// Colors and styles of the current theme, set by SetTheme
 var (
 	// Colors
-	Green   = lipgloss.Color("34")
-	Yellow  = lipgloss.Color("214")
-	Orange  = lipgloss.Color("208")
-	Red     = lipgloss.Color("196")
-	Purple  = lipgloss.Color("99")
-	Gray    = lipgloss.Color("241")
-	White   = lipgloss.Color("255")
-	Cyan    = lipgloss.Color("87")
-	Magenta = lipgloss.Color("213")
+	SuccessColor   color.Color
+	WarningColor   color.Color
+	ErrorColor     color.Color
+	MutedColor     color.Color
+	AccentColor    color.Color
+	OnAccentColor  color.Color
+	HighlightColor color.Color
 
 	// Feedback styles
-	SuccessStyle = lipgloss.NewStyle().Foreground(Green)
-	WarningStyle = lipgloss.NewStyle().Foreground(Yellow)
-	ErrorStyle   = lipgloss.NewStyle().Foreground(Red)
-	MutedStyle   = lipgloss.NewStyle().Foreground(Gray)
+	SuccessStyle lipgloss.Style
+	WarningStyle lipgloss.Style
+	ErrorStyle   lipgloss.Style
+	MutedStyle   lipgloss.Style
 	BoldStyle    = lipgloss.NewStyle().Bold(true)
 
-	// Symbols
-	SuccessIcon = SuccessStyle.Render("✓")
-	WarningIcon = WarningStyle.Render("⚠")
-	ErrorIcon   = ErrorStyle.Render("✗")
-	ArrowIcon   = MutedStyle.Render("→")
+	// Symbols, ASCII without color
+	SuccessIcon string
+	WarningIcon string
+	ErrorIcon   string
+	ArrowIcon   string
+
+	// Arrow separates the two sides of a change, like renamed files
+	Arrow = "→"
 
 	// Status badge styles (with background)
-	StatusStyles = map[adr.Status]lipgloss.Style{
-		adr.StatusDraft:      lipgloss.NewStyle().Background(Gray).Foreground(White).Padding(0, 1),
-		adr.StatusProposed:   lipgloss.NewStyle().Background(Yellow).Foreground(lipgloss.Color("0")).Padding(0, 1),
-		adr.StatusAccepted:   lipgloss.NewStyle().Background(Green).Foreground(White).Padding(0, 1),
-		adr.StatusDeprecated: lipgloss.NewStyle().Background(Orange).Foreground(White).Padding(0, 1),
-		adr.StatusSuperseded: lipgloss.NewStyle().Background(Purple).Foreground(White).Padding(0, 1),
-		adr.StatusRejected:   lipgloss.NewStyle().Background(Red).Foreground(White).Padding(0, 1),
-	}
+	StatusStyles map[adr.Status]lipgloss.Style
 
 	// Table styles
-	TableHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(Cyan).Padding(0, 1)
+	TableHeaderStyle lipgloss.Style
 	TableCellStyle   = lipgloss.NewStyle().Padding(0, 1)
 	TableBorder      = lipgloss.RoundedBorder()
+	FrameBorder      = lipgloss.RoundedBorder()
 )
 
+// applyTheme derives the colors and styles from a theme
+func applyTheme(t *Theme) {
+	SuccessColor = lipgloss.Color(t.Success)
+	WarningColor = lipgloss.Color(t.Warning)
+	ErrorColor = lipgloss.Color(t.Error)
+	MutedColor = lipgloss.Color(t.Muted)
+	AccentColor = lipgloss.Color(t.Accent)
+	OnAccentColor = lipgloss.Color(t.OnAccent)
+	HighlightColor = lipgloss.Color(t.Highlight)
+
+	SuccessStyle = lipgloss.NewStyle().Foreground(SuccessColor)
+	WarningStyle = lipgloss.NewStyle().Foreground(WarningColor)
+	ErrorStyle = lipgloss.NewStyle().Foreground(ErrorColor)
+	MutedStyle = lipgloss.NewStyle().Foreground(MutedColor)
+
+	StatusStyles = make(map[adr.Status]lipgloss.Style, len(adr.ValidStatuses))
+	for _, status := range adr.ValidStatuses {
+		c := t.Status(status)
+		StatusStyles[status] = lipgloss.NewStyle().
+			Background(lipgloss.Color(c.Fill)).
+			Foreground(lipgloss.Color(c.Text)).
+			Padding(0, 1)
+	}
+
+	TableHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(AccentColor).Padding(0, 1)
+}
+
 // RenderStatus renders a status as a colored badge
 func RenderStatus(status adr.Status) string {
 	style, ok := StatusStyles[status]
@@ -59,7 +84,7 @@ func RenderStatus(status adr.Status) string {
 func RenderStatusTransition(from, to adr.Status) string {
 	fromStyle := StatusStyles[from]
 	toStyle := StatusStyles[to]
-	arrow := lipgloss.NewStyle().Foreground(Magenta).Render(" → ")
+	arrow := lipgloss.NewStyle().Foreground(HighlightColor).Render(" " + Arrow + " ")
 	return fromStyle.Render(string(from)) + arrow + toStyle.Render(string(to))
 }
 
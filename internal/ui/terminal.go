@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"os"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/term"
+)
+
+// IsTerminal reports whether f is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(f.Fd())
+}
+
+// Size returns the width and height of the terminal f is attached to, or
+// 80x24 when it isn't a terminal
+func Size(f *os.File) (width, height int) {
+	width, height, err := term.GetSize(f.Fd())
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// HasDarkBackground reports whether the terminal has a dark background. It
+// asks the terminal, so it assumes dark when stdin or stdout isn't one.
+func HasDarkBackground() bool {
+	if !IsTerminal(os.Stdin) || !IsTerminal(os.Stdout) {
+		return true
+	}
+	return lipgloss.HasDarkBackground(os.Stdin, os.Stdout)
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/stef16robbe/stamp/internal/adr"
+)
+
+// DefaultTheme is the theme used unless the theme setting names another
+const DefaultTheme = "dark"
+
+// StatusColors are the colors of a status badge in the terminal, and of its
+// nodes in graphs and the HTML export. Colors are hex, like "#22c55e".
+type StatusColors struct {
+	Fill   string // background of badges and nodes
+	Text   string // text on the fill
+	Stroke string // border of graph nodes
+}
+
+// Theme is every color stamp uses, in the terminal and in generated files
+type Theme struct {
+	Statuses map[adr.Status]StatusColors
+
+	Success   string
+	Warning   string
+	Error     string
+	Muted     string // secondary text and borders
+	Accent    string // table headers and ADR IDs
+	OnAccent  string // text on the accent color, like the title of 'stamp show'
+	Highlight string // arrows of relations and status changes
+}
+
+// Status returns the colors of a status, falling back to the draft colors
+func (t *Theme) Status(status adr.Status) StatusColors {
+	if c, ok := t.Statuses[status]; ok {
+		return c
+	}
+	return t.Statuses[adr.StatusDraft]
+}
+
+// defaultStatuses are the status colors of the dark and light themes
+var defaultStatuses = map[adr.Status]StatusColors{
+	adr.StatusDraft:      {Fill: "#6b7280", Text: "#ffffff", Stroke: "#374151"},
+	adr.StatusProposed:   {Fill: "#3b82f6", Text: "#ffffff", Stroke: "#1d4ed8"},
+	adr.StatusAccepted:   {Fill: "#22c55e", Text: "#ffffff", Stroke: "#15803d"},
+	adr.StatusDeprecated: {Fill: "#f59e0b", Text: "#000000", Stroke: "#d97706"},
+	adr.StatusSuperseded: {Fill: "#a855f7", Text: "#ffffff", Stroke: "#7e22ce"},
+	adr.StatusRejected:   {Fill: "#ef4444", Text: "#ffffff", Stroke: "#b91c1c"},
+}
+
+// Themes are the built-in themes
+var Themes = map[string]*Theme{
+	"dark": {
+		Statuses:  defaultStatuses,
+		Success:   "#00af00",
+		Warning:   "#ffaf00",
+		Error:     "#ff0000",
+		Muted:     "#626262",
+		Accent:    "#5fffff",
+		OnAccent:  "#000000",
+		Highlight: "#ff87ff",
+	},
+	"light": {
+		Statuses:  defaultStatuses,
+		Success:   "#15803d",
+		Warning:   "#b45309",
+		Error:     "#b91c1c",
+		Muted:     "#6b7280",
+		Accent:    "#0369a1",
+		OnAccent:  "#ffffff",
+		Highlight: "#a21caf",
+	},
+	// Saturated colors with black text and borders
+	"high-contrast": {
+		Statuses: map[adr.Status]StatusColors{
+			adr.StatusDraft:      {Fill: "#ffffff", Text: "#000000", Stroke: "#000000"},
+			adr.StatusProposed:   {Fill: "#ffff00", Text: "#000000", Stroke: "#000000"},
+			adr.StatusAccepted:   {Fill: "#00ff00", Text: "#000000", Stroke: "#000000"},
+			adr.StatusDeprecated: {Fill: "#ff8700", Text: "#000000", Stroke: "#000000"},
+			adr.StatusSuperseded: {Fill: "#00ffff", Text: "#000000", Stroke: "#000000"},
+			adr.StatusRejected:   {Fill: "#ff0000", Text: "#ffffff", Stroke: "#000000"},
+		},
+		Success:   "#00ff00",
+		Warning:   "#ffff00",
+		Error:     "#ff5f5f",
+		Muted:     "#d0d0d0",
+		Accent:    "#00ffff",
+		OnAccent:  "#000000",
+		Highlight: "#ff00ff",
+	},
+	// The Okabe-Ito palette, told apart with any color vision: accepted is
+	// blue and rejected vermillion rather than green and red
+	"colorblind": {
+		Statuses: map[adr.Status]StatusColors{
+			adr.StatusDraft:      {Fill: "#999999", Text: "#000000", Stroke: "#666666"},
+			adr.StatusProposed:   {Fill: "#f0e442", Text: "#000000", Stroke: "#b8ad00"},
+			adr.StatusAccepted:   {Fill: "#0072b2", Text: "#ffffff", Stroke: "#004c77"},
+			adr.StatusDeprecated: {Fill: "#e69f00", Text: "#000000", Stroke: "#a67300"},
+			adr.StatusSuperseded: {Fill: "#cc79a7", Text: "#000000", Stroke: "#99507a"},
+			adr.StatusRejected:   {Fill: "#d55e00", Text: "#ffffff", Stroke: "#993f00"},
+		},
+		Success:   "#56b4e9",
+		Warning:   "#e69f00",
+		Error:     "#d55e00",
+		Muted:     "#8a8a8a",
+		Accent:    "#56b4e9",
+		OnAccent:  "#000000",
+		Highlight: "#cc79a7",
+	},
+}
+
+// ThemeNames returns the names of the built-in themes, sorted
+func ThemeNames() []string {
+	return slices.Sorted(maps.Keys(Themes))
+}
+
+// LookupTheme returns the built-in theme called name
+func LookupTheme(name string) (*Theme, error) {
+	t, ok := Themes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q (built-in: %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	return t, nil
+}
+
+// current is the theme set with SetTheme
+var current = Themes[DefaultTheme]
+
+// CurrentTheme returns the theme in use
+func CurrentTheme() *Theme {
+	return current
+}
+
+// SetTheme makes t the theme of all styles
+func SetTheme(t *Theme) {
+	current = t
+	applyTheme(t)
+	setIcons(HasColor())
+}
+
+// Override returns a copy of t with the colors set in o. Status colors are
+// overridden one by one.
+func (t *Theme) Override(o *Theme) *Theme {
+	result := *t
+	result.Statuses = maps.Clone(t.Statuses)
+	for status, c := range o.Statuses {
+		base := result.Statuses[status]
+		result.Statuses[status] = StatusColors{
+			Fill:   cmp.Or(c.Fill, base.Fill),
+			Text:   cmp.Or(c.Text, base.Text),
+			Stroke: cmp.Or(c.Stroke, base.Stroke),
+		}
+	}
+	result.Success = cmp.Or(o.Success, t.Success)
+	result.Warning = cmp.Or(o.Warning, t.Warning)
+	result.Error = cmp.Or(o.Error, t.Error)
+	result.Muted = cmp.Or(o.Muted, t.Muted)
+	result.Accent = cmp.Or(o.Accent, t.Accent)
+	result.OnAccent = cmp.Or(o.OnAccent, t.OnAccent)
+	result.Highlight = cmp.Or(o.Highlight, t.Highlight)
+	return &result
+}
@@ -0,0 +1,248 @@
+// Package workspace aggregates the ADRs of several local stamp projects,
+// such as the checkouts of an organization's service repositories.
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/config"
+)
+
+// FileName is the name of the workspace file in the user's config directory
+const FileName = "workspace.yaml"
+
+// Workspace is the list of projects read by 'stamp workspace'
+type Workspace struct {
+	Projects []Project `yaml:"projects"`
+}
+
+// Project is a local checkout containing a .stamp.yaml
+type Project struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+}
+
+// DefaultPath returns the workspace file in the user's config directory,
+// e.g. ~/.config/stamp/workspace.yaml
+func DefaultPath() (string, error) {
+	dir, err := config.UserDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Load reads a workspace file. A missing file is an empty workspace.
+func Load(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Workspace{}, nil
+		}
+		return nil, err
+	}
+
+	var w Workspace
+	if err := yaml.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &w, nil
+}
+
+// Save writes the workspace file, creating its directory if needed
+func (w *Workspace) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(w)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Add adds the project at path under name, which defaults to the name of the
+// project's directory
+func (w *Workspace) Add(path, name string) (*Project, error) {
+	abs, err := filepath.Abs(expandHome(path))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := config.LoadFrom(abs); err != nil {
+		return nil, fmt.Errorf("%s is not a stamp project: %w", path, err)
+	}
+
+	if name == "" {
+		name = filepath.Base(abs)
+	}
+	for _, p := range w.Projects {
+		if p.Name == name {
+			return nil, fmt.Errorf("project %q is already in the workspace", name)
+		}
+	}
+
+	w.Projects = append(w.Projects, Project{Name: name, Path: abs})
+	return &w.Projects[len(w.Projects)-1], nil
+}
+
+// Remove removes the project called name
+func (w *Workspace) Remove(name string) error {
+	for i, p := range w.Projects {
+		if p.Name == name {
+			w.Projects = append(w.Projects[:i], w.Projects[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("project %q is not in the workspace", name)
+}
+
+// expandHome replaces a leading "~/" with the user's home directory
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// Entry is an ADR of a workspace project
+type Entry struct {
+	Project    string
+	Collection string // empty for projects with a single ADR directory
+	Directory  string
+	Scheme     adr.Scheme
+	ADR        *adr.ADR
+}
+
+// Qualifier names the ADR log the entry belongs to, like "payments" or
+// "monorepo/billing" for a collection of a project
+func (e *Entry) Qualifier() string {
+	if e.Collection == "" {
+		return e.Project
+	}
+	return e.Project + "/" + e.Collection
+}
+
+// ID returns the ADR number qualified with its project, like "payments:0003"
+func (e *Entry) ID() string {
+	return e.Qualifier() + ":" + e.Scheme.FormatNumber(e.ADR.Number)
+}
+
+// Path returns the absolute path of the ADR file
+func (e *Entry) Path() string {
+	return filepath.Join(e.Directory, e.ADR.Filename)
+}
+
+// Collect loads the ADRs of every project, in workspace order. Projects that
+// cannot be read and ADR files that fail to parse don't stop the others;
+// they are returned as warnings.
+func (w *Workspace) Collect() ([]*Entry, []error) {
+	var entries []*Entry
+	var warnings []error
+
+	for _, p := range w.Projects {
+		cfg, err := config.LoadFrom(expandHome(p.Path))
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("%s: %w", p.Name, err))
+			continue
+		}
+
+		cols, err := cfg.ResolveCollections()
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("%s: %w", p.Name, err))
+			continue
+		}
+
+		for _, col := range cols {
+			adrs, diagnostics, err := col.Store().List()
+			if err != nil {
+				if !errors.Is(err, os.ErrNotExist) {
+					warnings = append(warnings, fmt.Errorf("%s: %w", p.Name, err))
+				}
+				continue
+			}
+			for _, d := range diagnostics {
+				warnings = append(warnings, fmt.Errorf("%s: %w", p.Name, d))
+			}
+			for _, a := range adrs {
+				entries = append(entries, &Entry{Project: p.Name, Collection: col.Name, Directory: col.Directory, Scheme: col.Scheme, ADR: a})
+			}
+		}
+	}
+
+	return entries, warnings
+}
+
+// Search returns the entries whose title, context, decision or consequences
+// contain every word of query, ignoring case
+func Search(entries []*Entry, query string) []*Entry {
+	words := strings.Fields(strings.ToLower(query))
+
+	var matches []*Entry
+	for _, e := range entries {
+		text := strings.ToLower(strings.Join([]string{
+			e.ADR.Title, e.ADR.Context, e.ADR.Decision, e.ADR.Consequences,
+		}, "\n"))
+
+		found := true
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				found = false
+				break
+			}
+		}
+		if found {
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
+
+// linkLineRegex matches the link lines written by 'stamp link', capturing
+// the relation and the link target
+var linkLineRegex = regexp.MustCompile(`^(Supersedes|Amends|Clarifies)\s+\[[A-Za-z][A-Za-z0-9]*-\d+\]\(([^)]+)\)`)
+
+// Edge is a relation between two workspace entries
+type Edge struct {
+	Source   *Entry
+	Target   *Entry
+	Relation string
+}
+
+// Edges returns the forward relations between entries, including those
+// between collections of the same project. Link targets are resolved as
+// paths relative to the linking ADR, so ADRs of different projects with the
+// same number are never confused.
+func Edges(entries []*Entry) []Edge {
+	byPath := make(map[string]*Entry, len(entries))
+	for _, e := range entries {
+		byPath[e.Path()] = e
+	}
+
+	var edges []Edge
+	seen := make(map[[2]*Entry]bool)
+	for _, e := range entries {
+		for _, line := range e.ADR.StatusExtra {
+			match := linkLineRegex.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			target, ok := byPath[filepath.Join(e.Directory, filepath.FromSlash(match[2]))]
+			if !ok || seen[[2]*Entry{e, target}] {
+				continue
+			}
+			seen[[2]*Entry{e, target}] = true
+			edges = append(edges, Edge{Source: e, Target: target, Relation: match[1]})
+		}
+	}
+	return edges
+}
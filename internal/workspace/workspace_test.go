@@ -0,0 +1,176 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stef16robbe/stamp/internal/adr"
+	"github.com/stef16robbe/stamp/internal/config"
+)
+
+// writeProject creates a stamp project in dir with the given ADRs in adrDir
+func writeProject(t *testing.T, dir string, cfg *config.Config, adrDir string, adrs ...*adr.ADR) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(dir, adrDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(dir); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	store := adr.NewStore(filepath.Join(dir, adrDir))
+	for _, a := range adrs {
+		if err := store.Save(a); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+}
+
+func newADR(number int, title, decision string, links ...string) *adr.ADR {
+	return &adr.ADR{
+		Number:      number,
+		Title:       title,
+		Date:        time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Status:      adr.StatusAccepted,
+		StatusExtra: links,
+		Context:     "Context",
+		Decision:    decision,
+	}
+}
+
+func testWorkspace(t *testing.T) *Workspace {
+	t.Helper()
+	root := t.TempDir()
+
+	payments := filepath.Join(root, "payments")
+	writeProject(t, payments, &config.Config{Directory: "docs/adr"}, "docs/adr",
+		newADR(1, "Use Stripe", "We use Stripe for card payments"),
+		newADR(2, "Use Kafka", "Events go through Kafka", "Supersedes [ADR-0001](0001-use-stripe.md)"),
+	)
+
+	mono := filepath.Join(root, "mono")
+	monoCfg := &config.Config{Collections: map[string]config.Collection{
+		"platform": {Directory: "docs/adr"},
+		"billing":  {Directory: "billing/adr", IDs: config.IDs{Prefix: "BIL"}},
+	}}
+	writeProject(t, mono, monoCfg, "docs/adr",
+		newADR(1, "Use Postgres", "Postgres everywhere", "Amends [BIL-0001](../../billing/adr/0001-use-kafka-for-invoices.md)"),
+	)
+	writeProject(t, mono, monoCfg, "billing/adr",
+		newADR(1, "Use Kafka for invoices", "Invoices are events"),
+	)
+
+	w := &Workspace{}
+	if _, err := w.Add(payments, ""); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	if _, err := w.Add(mono, "monorepo"); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	w.Projects = append(w.Projects, Project{Name: "gone", Path: filepath.Join(root, "gone")})
+	return w
+}
+
+func TestCollect(t *testing.T) {
+	w := testWorkspace(t)
+
+	entries, warnings := w.Collect()
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %v, want one for the missing project", warnings)
+	}
+
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.ID())
+	}
+	want := []string{"payments:0001", "payments:0002", "monorepo/billing:0001", "monorepo/platform:0001"}
+	if len(ids) != len(want) {
+		t.Fatalf("IDs = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("IDs = %v, want %v", ids, want)
+			break
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	entries, _ := testWorkspace(t).Collect()
+
+	matches := Search(entries, "KAFKA")
+	if len(matches) != 2 {
+		t.Fatalf("Search(KAFKA) returned %d entries, want 2", len(matches))
+	}
+
+	matches = Search(entries, "kafka invoices")
+	if len(matches) != 1 || matches[0].ID() != "monorepo/billing:0001" {
+		t.Errorf("Search(kafka invoices) = %v, want monorepo/billing:0001", matches)
+	}
+}
+
+func TestEdges(t *testing.T) {
+	entries, _ := testWorkspace(t).Collect()
+
+	edges := Edges(entries)
+	if len(edges) != 2 {
+		t.Fatalf("Edges() returned %d edges, want 2", len(edges))
+	}
+
+	got := map[string]string{}
+	for _, e := range edges {
+		got[e.Source.ID()] = e.Relation + " " + e.Target.ID()
+	}
+	if got["payments:0002"] != "Supersedes payments:0001" {
+		t.Errorf("edge from payments:0002 = %q", got["payments:0002"])
+	}
+	if got["monorepo/platform:0001"] != "Amends monorepo/billing:0001" {
+		t.Errorf("edge from monorepo/platform:0001 = %q", got["monorepo/platform:0001"])
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	w := testWorkspace(t)
+
+	if _, err := w.Add(w.Projects[0].Path, ""); err == nil {
+		t.Error("Add() expected error for duplicate name")
+	}
+	if _, err := w.Add(t.TempDir(), ""); err == nil {
+		t.Error("Add() expected error for a directory without .stamp.yaml")
+	}
+
+	if err := w.Remove("payments"); err != nil {
+		t.Fatalf("Remove() error: %v", err)
+	}
+	if err := w.Remove("payments"); err == nil {
+		t.Error("Remove() expected error for unknown project")
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stamp", FileName)
+
+	w, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of missing file error: %v", err)
+	}
+	if len(w.Projects) != 0 {
+		t.Errorf("Load() of missing file returned %d projects", len(w.Projects))
+	}
+
+	w.Projects = []Project{{Name: "payments", Path: "/src/payments"}}
+	if err := w.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(loaded.Projects) != 1 || loaded.Projects[0] != w.Projects[0] {
+		t.Errorf("Load() = %+v, want %+v", loaded.Projects, w.Projects)
+	}
+}